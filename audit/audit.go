@@ -0,0 +1,158 @@
+// Package audit records every RPC a server handles, with its inputs,
+// outputs, caller and outcome, to an append-only Log.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"go-grpc/caller"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxStreamMessages bounds how many messages are recorded per direction
+	// of a stream, so that long-lived streams do not produce unbounded
+	// entries.
+	maxStreamMessages = 1000
+	// maxLineBytes bounds the size of a single entry when reading the log.
+	maxLineBytes = 64 << 20
+)
+
+// Entry is the record of one RPC. Messages are stored in their protobuf JSON
+// form.
+type Entry struct {
+	Sequence  uint64            `json:"sequence"`
+	Time      time.Time         `json:"time"`
+	Duration  time.Duration     `json:"duration"`
+	Method    string            `json:"method"`
+	Caller    string            `json:"caller"`
+	Peer      string            `json:"peer"`
+	Requests  []json.RawMessage `json:"requests,omitempty"`
+	Responses []json.RawMessage `json:"responses,omitempty"`
+	// Truncated is set if a stream carried more messages than were recorded.
+	Truncated bool   `json:"truncated,omitempty"`
+	Code      string `json:"code"`
+	Message   string `json:"message,omitempty"`
+}
+
+// Recorder provides interceptors that append an Entry to a Log for every
+// RPC. Failing to write an entry is logged rather than failing the RPC.
+type Recorder struct {
+	log  *Log
+	omit map[string]bool
+}
+
+// NewRecorder returns a Recorder writing to l. The responses of the methods
+// in omitResponses are not recorded, which keeps methods serving the audit
+// log itself from copying it into new entries.
+func NewRecorder(l *Log, omitResponses ...string) *Recorder {
+	r := &Recorder{log: l, omit: make(map[string]bool)}
+	for _, m := range omitResponses {
+		r.omit[m] = true
+	}
+	return r
+}
+
+// UnaryInterceptor returns an interceptor recording unary RPCs.
+func (r *Recorder) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		e := newEntry(ctx, info.FullMethod)
+		e.add(&e.Requests, req)
+		resp, err := handler(ctx, req)
+		if err == nil && !r.omit[info.FullMethod] {
+			e.add(&e.Responses, resp)
+		}
+		r.finish(e, err)
+		return resp, err
+	}
+}
+
+// StreamInterceptor returns an interceptor recording streaming RPCs,
+// including every message received and sent on the stream.
+func (r *Recorder) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		e := newEntry(ss.Context(), info.FullMethod)
+		err := handler(srv, &serverStream{ServerStream: ss, entry: e, omit: r.omit[info.FullMethod]})
+		r.finish(e, err)
+		return err
+	}
+}
+
+type entry struct {
+	mu    sync.Mutex
+	start time.Time
+	Entry
+}
+
+func newEntry(ctx context.Context, method string) *entry {
+	now := time.Now()
+	return &entry{
+		start: now,
+		Entry: Entry{
+			Time:   now,
+			Method: method,
+			Caller: caller.ID(ctx),
+			Peer:   caller.Addr(ctx),
+		},
+	}
+}
+
+// add appends the JSON form of m to msgs.
+func (e *entry) add(msgs *[]json.RawMessage, m interface{}) {
+	pm, ok := m.(proto.Message)
+	if !ok {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(*msgs) >= maxStreamMessages {
+		e.Truncated = true
+		return
+	}
+	b, err := protojson.Marshal(pm)
+	if err != nil {
+		b, _ = json.Marshal(err.Error())
+	}
+	*msgs = append(*msgs, b)
+}
+
+func (r *Recorder) finish(e *entry, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	st := status.Convert(err)
+	e.Code = st.Code().String()
+	e.Message = st.Message()
+	e.Duration = time.Since(e.start)
+	if err := r.log.Append(&e.Entry); err != nil {
+		log.Printf("Failed to write audit log entry: %v", err)
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	entry *entry
+	omit  bool
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.entry.add(&s.entry.Requests, m)
+	}
+	return err
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil && !s.omit {
+		s.entry.add(&s.entry.Responses, m)
+	}
+	return err
+}
@@ -0,0 +1,331 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidPageToken is returned by Log.Page for tokens it did not issue.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// Log is an append-only JSON lines file holding one Entry per line.
+//
+// Once the file would grow beyond MaxBytes it is rotated: path becomes
+// path.1, path.1 becomes path.2 and so on, keeping at most MaxFiles rotated
+// files. Entries are numbered with a sequence that keeps increasing across
+// rotations and restarts.
+type Log struct {
+	path     string
+	maxBytes int64
+	maxFiles int
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+	seq  uint64
+}
+
+// Open opens the log at path for appending, creating it if needed. A
+// maxBytes of zero disables rotation.
+func Open(path string, maxBytes int64, maxFiles int) (*Log, error) {
+	l := &Log{
+		path:     path,
+		maxBytes: maxBytes,
+		maxFiles: maxFiles,
+	}
+	// Continue the sequence from the newest entry on disk.
+	files, err := l.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	defer closeFiles(files)
+	for _, f := range files {
+		err := scan(f, 0, func(e *Entry, _ int64) bool {
+			l.seq = e.Sequence
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Log) open() error {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.f, l.size = f, fi.Size()
+	return nil
+}
+
+// Append assigns e the next sequence number and writes it to the log.
+func (l *Log) Append(e *Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.Sequence = l.seq + 1
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if l.maxBytes > 0 && l.size > 0 && l.size+int64(len(b)) > l.maxBytes {
+		if err := l.rotate(); err != nil {
+			return fmt.Errorf("rotating audit log: %v", err)
+		}
+	}
+	n, err := l.f.Write(b)
+	l.size += int64(n)
+	if err != nil {
+		return err
+	}
+	l.seq = e.Sequence
+	return nil
+}
+
+// rotate shifts the rotated files up by one and starts a new file. l.mu must
+// be held.
+func (l *Log) rotate() error {
+	if err := l.f.Close(); err != nil {
+		return err
+	}
+	if l.maxFiles < 1 {
+		if err := os.Remove(l.path); err != nil {
+			return err
+		}
+		return l.open()
+	}
+	for i := l.maxFiles - 1; i >= 1; i-- {
+		err := os.Rename(l.rotated(i), l.rotated(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(l.path, l.rotated(1)); err != nil {
+		return err
+	}
+	return l.open()
+}
+
+func (l *Log) rotated(i int) string {
+	return l.path + "." + strconv.Itoa(i)
+}
+
+// files lists the files of the log from oldest to newest.
+func (l *Log) files() []string {
+	var paths []string
+	for i := l.maxFiles; i >= 1; i-- {
+		if _, err := os.Stat(l.rotated(i)); err == nil {
+			paths = append(paths, l.rotated(i))
+		}
+	}
+	return append(paths, l.path)
+}
+
+// file is a file of the log opened for reading, of which only the first
+// size bytes hold whole entries.
+type file struct {
+	*os.File
+	size int64
+}
+
+// snapshot opens the files of the log, from oldest to newest. Open files are
+// unaffected by later rotations and only their entries at the time of the
+// snapshot are read, so l.mu is only held while opening them.
+func (l *Log) snapshot() ([]file, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var files []file
+	for _, p := range l.files() {
+		f, err := os.Open(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err == nil {
+			var fi os.FileInfo
+			if fi, err = f.Stat(); err == nil {
+				files = append(files, file{f, fi.Size()})
+				continue
+			}
+			f.Close()
+		}
+		closeFiles(files)
+		return nil, err
+	}
+	return files, nil
+}
+
+func closeFiles(files []file) {
+	for _, f := range files {
+		f.Close()
+	}
+}
+
+// Page returns up to size entries in sequence order, starting at the one
+// named by token, or at the oldest one if token is empty. Only entries whose
+// method is method are returned, unless method is empty. The returned token
+// names the next page and is empty once nothing is left.
+//
+// A token holds the sequence of the next entry and its offset in its file,
+// so pages are read from where the previous one stopped instead of from the
+// start of the log. Appending is not blocked while a page is read.
+func (l *Log) Page(token string, size int, method string) ([]*Entry, string, error) {
+	from, offset, err := parseToken(token)
+	if err != nil {
+		return nil, "", err
+	}
+	files, err := l.snapshot()
+	if err != nil {
+		return nil, "", err
+	}
+	defer closeFiles(files)
+
+	// Start at the newest file whose first entry is not after from, at the
+	// offset of the token if an entry starts there and is the expected one.
+	// Tokens come from clients, and rotations and restarts may have moved
+	// the entry, so in any other case the file is read from its start.
+	first, start := 0, int64(0)
+	if from > 0 && len(files) > 0 {
+		for i, f := range files {
+			e, err := entryAt(f, 0)
+			if err != nil {
+				return nil, "", err
+			}
+			if e != nil && e.Sequence <= from {
+				first = i
+			}
+		}
+		if offset > 0 {
+			e, err := entryAt(files[first], offset)
+			if err != nil {
+				return nil, "", err
+			}
+			if e != nil && e.Sequence == from {
+				start = offset
+			}
+		}
+	}
+
+	var entries []*Entry
+	var next string
+	for _, f := range files[first:] {
+		err := scan(f, start, func(e *Entry, offset int64) bool {
+			if e.Sequence < from || (method != "" && e.Method != method) {
+				return true
+			}
+			if len(entries) == size {
+				next = formatToken(e.Sequence, offset)
+				return false
+			}
+			entries = append(entries, e)
+			return true
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		if next != "" {
+			break
+		}
+		start = 0
+	}
+	return entries, next, nil
+}
+
+// parseToken returns the sequence and offset a page token holds. Tokens
+// holding only a sequence are accepted too, with an offset of zero.
+func parseToken(token string) (seq uint64, offset int64, err error) {
+	if token == "" {
+		return 0, 0, nil
+	}
+	s, o, hasOffset := token, "", false
+	if i := strings.IndexByte(token, '-'); i >= 0 {
+		s, o, hasOffset = token[:i], token[i+1:], true
+	}
+	if seq, err = strconv.ParseUint(s, 10, 64); err != nil {
+		return 0, 0, ErrInvalidPageToken
+	}
+	if hasOffset {
+		if offset, err = strconv.ParseInt(o, 10, 64); err != nil || offset < 0 {
+			return 0, 0, ErrInvalidPageToken
+		}
+	}
+	return seq, offset, nil
+}
+
+func formatToken(seq uint64, offset int64) string {
+	return strconv.FormatUint(seq, 10) + "-" + strconv.FormatInt(offset, 10)
+}
+
+// Close closes the log file.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.f.Close()
+}
+
+// entryAt returns the entry starting at offset in f, or nil if there is
+// none: offset is past the end of f, not at the start of a line, or the line
+// there is not an entry.
+func entryAt(f file, offset int64) (*Entry, error) {
+	if offset >= f.size {
+		return nil, nil
+	}
+	if offset > 0 {
+		b := make([]byte, 1)
+		if _, err := f.ReadAt(b, offset-1); err != nil {
+			return nil, err
+		}
+		if b[0] != '\n' {
+			return nil, nil
+		}
+	}
+	s := bufio.NewScanner(io.NewSectionReader(f, offset, f.size-offset))
+	s.Buffer(nil, maxLineBytes)
+	if !s.Scan() {
+		return nil, s.Err()
+	}
+	var e Entry
+	if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+		return nil, nil
+	}
+	return &e, nil
+}
+
+// scan calls fn for every entry of f from offset on, with the offset of the
+// entry, until fn returns false.
+func scan(f file, offset int64, fn func(e *Entry, offset int64) bool) error {
+	if offset >= f.size {
+		return nil
+	}
+	s := bufio.NewScanner(io.NewSectionReader(f, offset, f.size-offset))
+	s.Buffer(nil, maxLineBytes)
+	for s.Scan() {
+		var e Entry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			return fmt.Errorf("%v: %v", f.Name(), err)
+		}
+		if !fn(&e, offset) {
+			return nil
+		}
+		offset += int64(len(s.Bytes())) + 1
+	}
+	return s.Err()
+}
@@ -0,0 +1,142 @@
+package audit_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"go-grpc/audit"
+)
+
+func appendEntries(t *testing.T, l *audit.Log, methods ...string) {
+	t.Helper()
+	for _, m := range methods {
+		if err := l.Append(&audit.Entry{Method: m, Code: "OK"}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+}
+
+// pageAll pages through the log from token and returns the sequences of the
+// entries.
+func pageAll(t *testing.T, l *audit.Log, token string, size int, method string) []uint64 {
+	t.Helper()
+	var seqs []uint64
+	for {
+		entries, next, err := l.Page(token, size, method)
+		if err != nil {
+			t.Fatalf("Page(%q): %v", token, err)
+		}
+		for _, e := range entries {
+			seqs = append(seqs, e.Sequence)
+		}
+		if next == "" {
+			return seqs
+		}
+		token = next
+	}
+}
+
+func sequences(from, to, step uint64) []uint64 {
+	var seqs []uint64
+	for s := from; s <= to; s += step {
+		seqs = append(seqs, s)
+	}
+	return seqs
+}
+
+func equal(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPage(t *testing.T) {
+	// Entries are about 100 bytes, so files rotate every few entries.
+	l, err := audit.Open(filepath.Join(t.TempDir(), "audit.log"), 500, 100)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer l.Close()
+	for i := 0; i < 25; i++ {
+		appendEntries(t, l, "/a", "/b")
+	}
+
+	if got, want := pageAll(t, l, "", 7, ""), sequences(1, 50, 1); !equal(got, want) {
+		t.Errorf("all entries = %v, want %v", got, want)
+	}
+	if got, want := pageAll(t, l, "", 3, "/b"), sequences(2, 50, 2); !equal(got, want) {
+		t.Errorf("/b entries = %v, want %v", got, want)
+	}
+	// Tokens holding only a sequence start at that entry.
+	if got, want := pageAll(t, l, "45", 2, ""), sequences(45, 50, 1); !equal(got, want) {
+		t.Errorf("entries from 45 = %v, want %v", got, want)
+	}
+}
+
+func TestPageAcrossRotations(t *testing.T) {
+	l, err := audit.Open(filepath.Join(t.TempDir(), "audit.log"), 500, 100)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer l.Close()
+	appendEntries(t, l, "/a", "/a", "/a", "/a", "/a", "/a")
+
+	entries, next, err := l.Page("", 3, "")
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("Page = %v entries, %v", len(entries), err)
+	}
+	// The file holding the next entry is renamed by the rotations.
+	for i := 0; i < 10; i++ {
+		appendEntries(t, l, "/a")
+	}
+	if got, want := pageAll(t, l, next, 4, ""), sequences(4, 16, 1); !equal(got, want) {
+		t.Errorf("entries after rotations = %v, want %v", got, want)
+	}
+}
+
+// TestPageOffsets checks that tokens whose offset is not the one of their
+// entry, made up by clients or moved by rotations, are read from the start of
+// the file holding the entry.
+func TestPageOffsets(t *testing.T) {
+	l, err := audit.Open(filepath.Join(t.TempDir(), "audit.log"), 500, 100)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer l.Close()
+	// Methods of different lengths, so that entries start at uneven offsets.
+	appendEntries(t, l, "/a", "/bb", "/ccc", "/dddd", "/a", "/bb", "/ccc", "/dddd", "/a", "/bb")
+
+	for offset := 0; offset < 1000; offset++ {
+		token := fmt.Sprintf("3-%d", offset)
+		entries, _, err := l.Page(token, 1, "")
+		if err != nil {
+			t.Fatalf("Page(%q): %v", token, err)
+		}
+		if len(entries) != 1 || entries[0].Sequence != 3 {
+			t.Fatalf("Page(%q) = %v entries, want entry 3", token, len(entries))
+		}
+	}
+
+	_, next, err := l.Page("", 6, "")
+	if err != nil {
+		t.Fatalf("Page: %v", err)
+	}
+	// Entry 7 moves to another file, at another offset.
+	appendEntries(t, l, "/a", "/bb", "/ccc", "/dddd", "/a", "/bb")
+	if got, want := pageAll(t, l, next, 5, ""), sequences(7, 16, 1); !equal(got, want) {
+		t.Errorf("entries after rotations = %v, want %v", got, want)
+	}
+
+	for _, token := range []string{"x", "3-", "3-x", "-3", "3--1"} {
+		if _, _, err := l.Page(token, 1, ""); err != audit.ErrInvalidPageToken {
+			t.Errorf("Page(%q) = %v, want ErrInvalidPageToken", token, err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package bidi
+
+import (
+	"google.golang.org/grpc"
+)
+
+// Receive receives the requests of a server stream on a new goroutine, so
+// that the handler can keep sending meanwhile. Requests are created with
+// newRequest and delivered on the first channel. The error ending the
+// stream, io.EOF if the client closed its side, is then delivered on the
+// second one. The goroutine exits once the stream ends.
+func Receive(stream grpc.ServerStream, newRequest func() interface{}) (<-chan interface{}, <-chan error) {
+	reqs := make(chan interface{})
+	errc := make(chan error, 1)
+	go func() {
+		done := stream.Context().Done()
+		for {
+			req := newRequest()
+			if err := stream.RecvMsg(req); err != nil {
+				errc <- err
+				return
+			}
+			select {
+			case reqs <- req:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return reqs, errc
+}
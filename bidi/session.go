@@ -0,0 +1,111 @@
+package bidi
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// ErrSendClosed is returned by Session.Send after CloseSend.
+var ErrSendClosed = errors.New("send side of the stream is closed")
+
+// Config describes the messages of a Session.
+type Config struct {
+	// NewResponse returns an empty response to receive into.
+	NewResponse func() interface{}
+	// Handle is called with every response, in order, on the receiving
+	// goroutine. Returning an error stops receiving and makes Wait return
+	// it, after which the caller should cancel the stream.
+	Handle func(resp interface{}) error
+	// Ack, if set, returns the request acknowledging resp, which is sent
+	// right after Handle returns. It may return nil for responses that need
+	// no acknowledgement.
+	Ack func(resp interface{}) interface{}
+}
+
+// Session runs the client side of a bidirectional stream: the caller sends
+// requests while the session receives responses on its own goroutine.
+type Session struct {
+	stream grpc.ClientStream
+	cfg    Config
+
+	mu     sync.Mutex
+	closed bool
+
+	done chan struct{}
+	err  error
+}
+
+// NewSession starts receiving the responses of stream.
+func NewSession(stream grpc.ClientStream, cfg Config) *Session {
+	s := &Session{
+		stream: stream,
+		cfg:    cfg,
+		done:   make(chan struct{}),
+	}
+	go s.receive()
+	return s
+}
+
+// Send sends req. An io.EOF error means the stream has ended, and Wait
+// returns why.
+func (s *Session) Send(req interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrSendClosed
+	}
+	return s.stream.SendMsg(req)
+}
+
+// CloseSend closes the send side of the stream. Responses keep being
+// received until the server ends the stream.
+func (s *Session) CloseSend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.stream.CloseSend()
+}
+
+// Wait blocks until the stream has ended and returns the error it ended
+// with, nil if the server ended it successfully.
+func (s *Session) Wait() error {
+	<-s.done
+	return s.err
+}
+
+// Done is closed once the stream has ended.
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Session) receive() {
+	defer close(s.done)
+	for {
+		resp := s.cfg.NewResponse()
+		if err := s.stream.RecvMsg(resp); err != nil {
+			if err != io.EOF {
+				s.err = err
+			}
+			return
+		}
+		if err := s.cfg.Handle(resp); err != nil {
+			s.err = err
+			return
+		}
+		if s.cfg.Ack == nil {
+			continue
+		}
+		// Send errors are ignored: after CloseSend the server no longer
+		// expects acknowledgements, and a broken stream makes the next
+		// RecvMsg fail with the reason.
+		if ack := s.cfg.Ack(resp); ack != nil {
+			s.Send(ack)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Package bidi helps run bidirectional streams.
+//
+// Servers numbering their responses can let clients bound how many of them
+// are in flight: a client sends the x-flow-window metadata with the number
+// of unacknowledged responses it accepts, and acknowledges responses by
+// sending their sequence number back. Acknowledgements are cumulative.
+// Once the client closes its side of the stream it can no longer send any,
+// so the window stops applying and the server sends what it has left.
+//
+// Session runs the client side of a stream, acknowledging responses if asked
+// to, and Receive the receiving half of the server side.
+package bidi
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// WindowKey is the metadata key a client sets to the number of
+// unacknowledged responses it accepts.
+const WindowKey = "x-flow-window"
+
+// MaxQueued bounds the responses a server queues for a client whose window is
+// full. Clients that keep sending requests without acknowledging anything
+// past that are disconnected.
+const MaxQueued = 1024
+
+// WithWindow returns a context making the streams opened with it limit the
+// unacknowledged responses to size.
+func WithWindow(ctx context.Context, size int) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, WindowKey, strconv.Itoa(size))
+}
+
+// Window tracks the responses a server sent on a stream and those the client
+// acknowledged. It is not safe for concurrent use.
+type Window struct {
+	size   uint64
+	sent   uint64
+	acked  uint64
+	closed bool
+}
+
+// WindowFromContext returns the window asked for by the client of an incoming
+// stream. Streams without the x-flow-window metadata get an unlimited window.
+func WindowFromContext(ctx context.Context) (*Window, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	v := md.Get(WindowKey)
+	if len(v) == 0 {
+		return &Window{}, nil
+	}
+	size, err := strconv.ParseUint(v[0], 10, 32)
+	if err != nil || size == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid %v metadata %q: must be a positive integer", WindowKey, v[0])
+	}
+	return &Window{size: size}, nil
+}
+
+// Open reports whether another response may be sent.
+func (w *Window) Open() bool {
+	return w.size == 0 || w.closed || w.sent-w.acked < w.size
+}
+
+// Next returns the sequence number of the next response, counting it as
+// sent.
+func (w *Window) Next() uint64 {
+	w.sent++
+	return w.sent
+}
+
+// Ack acknowledges every response up to seq.
+func (w *Window) Ack(seq uint64) error {
+	if seq > w.sent {
+		return status.Errorf(codes.InvalidArgument, "Acknowledged response %v but only %v were sent", seq, w.sent)
+	}
+	if seq > w.acked {
+		w.acked = seq
+	}
+	return nil
+}
+
+// Close stops limiting the responses, once the client can no longer
+// acknowledge them.
+func (w *Window) Close() {
+	w.closed = true
+}
@@ -2,19 +2,56 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"go-grpc/bidi"
 	"go-grpc/calculator/calculatorpb"
+	"go-grpc/loadbalancing"
+	"go-grpc/resume"
+	"go-grpc/rpcerror"
+	"go-grpc/serviceconfig"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	"io"
 	"log"
+	"math"
+	"strconv"
 	"time"
 )
 
+var (
+	target        = flag.String("target", "localhost:50051", "server address, comma-separated list of addresses, or dns:///, static:/// or file:/// target")
+	lbPolicy      = flag.String("lb_policy", "round_robin", "load balancing policy: pick_first, round_robin or least_request")
+	healthCheck   = flag.Bool("health_check", true, "skip backends whose health service does not report SERVING")
+	parallelism   = flag.Int("parallelism", 0, "number of CalculateStream jobs the server may run at once, 0 for its default")
+	flowWindow    = flag.Int("flow_window", 0, "maximum number of unacknowledged CalculateStreamingMax responses, 0 to disable flow control")
+	serviceConfig = flag.String("service_config", "calculator/calculator_client/service_config.json", "path to the JSON service config with retry and hedging policies, empty to disable")
+)
+
 func main() {
+	flag.Parse()
 	fmt.Println("Hello, I'm a client.")
-	cc, err := grpc.Dial("localhost:50051", grpc.WithInsecure())
+
+	sc, err := serviceconfig.Parse("{}")
+	if *serviceConfig != "" {
+		sc, err = serviceconfig.Load(*serviceConfig)
+	}
+	if err != nil {
+		log.Fatalf("Could not load service config: %v", err)
+	}
+	var healthCheckService *string
+	if *healthCheck {
+		healthCheckService = new(string)
+	}
+	if err := sc.SetLoadBalancing(*lbPolicy, healthCheckService); err != nil {
+		log.Fatalf("Could not configure load balancing: %v", err)
+	}
+	opts := append([]grpc.DialOption{grpc.WithInsecure()}, sc.DialOptions()...)
+
+	cc, err := grpc.Dial(loadbalancing.Target(*target), opts...)
 	if err != nil {
 		log.Fatalf("Could not connect: %v", err)
 	}
@@ -30,6 +67,10 @@ func main() {
 	// doBiDiStreaming(c)
 
 	doErrorUnary(c)
+	// doRoots(c)
+	// doGetHistory(c)
+	// doBatch(c)
+	// doCalculateStream(c)
 }
 
 func doUnary(c calculatorpb.CalculatorServiceClient) {
@@ -40,7 +81,7 @@ func doUnary(c calculatorpb.CalculatorServiceClient) {
 	}
 	res, err := c.Calculate(context.Background(), req)
 	if err != nil {
-		log.Fatalf("Error while calling Geret RPC: %v", err)
+		log.Fatalf("Error while calling Geret RPC: %v", rpcerror.Describe(err))
 	}
 	log.Printf("Response from Calculate: %v", res.Sum)
 }
@@ -49,10 +90,22 @@ func doServerStreaming(c calculatorpb.CalculatorServiceClient) {
 	req := &calculatorpb.CalculatorStreamingRequest{
 		X: 1277124,
 	}
-	resStream, err := c.CalculatePrimeStreaming(context.Background(), req)
-	if err != nil {
-		log.Fatalf("Error with the streaming request %v", err)
-	}
+	// If the connection drops, the stream is reopened from the last factor
+	// received.
+	resStream := resume.NewStream(context.Background(), func(ctx context.Context, token string) (resume.RecvFunc, error) {
+		req.ResumeToken = token
+		stream, err := c.CalculatePrimeStreaming(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (resume.Message, error) {
+			msg, err := stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+			return msg, nil
+		}, nil
+	}, resume.Options{})
 	for {
 		msg, err := resStream.Recv()
 		if err == io.EOF {
@@ -60,10 +113,10 @@ func doServerStreaming(c calculatorpb.CalculatorServiceClient) {
 		}
 
 		if err != nil {
-			log.Fatalf("Error with the message %v", err)
+			log.Fatalf("Error with the message %v", rpcerror.Describe(err))
 		}
 
-		log.Printf("Prime number %v", msg.X)
+		log.Printf("Prime number %v", msg.(*calculatorpb.CalculatorStreamingResponse).GetX())
 	}
 }
 
@@ -95,49 +148,52 @@ func doClientStreaming(c calculatorpb.CalculatorServiceClient) {
 
 	resp, err := stream.CloseAndRecv()
 	if err != nil {
-		log.Fatalf("Uh oh, spaghetti-o: %v", err)
+		log.Fatalf("Uh oh, spaghetti-o: %v", rpcerror.Describe(err))
 	}
 	fmt.Printf("Average is: %v\n", resp)
 }
 
 func doBiDiStreaming(c calculatorpb.CalculatorServiceClient) {
 	fmt.Println("Starting to do BiDi streaming")
-	stream, err := c.CalculateStreamingMax(context.Background())
+	ctx := context.Background()
+	if *flowWindow > 0 {
+		ctx = bidi.WithWindow(ctx, *flowWindow)
+	}
+	stream, err := c.CalculateStreamingMax(ctx)
 	if err != nil {
 		log.Fatalf("Error starting to stream to server: %v", err)
 	}
 
-	waitc := make(chan struct{})
-	requests := []int32{1, 5, 3, 6, 2, 20}
+	cfg := bidi.Config{
+		NewResponse: func() interface{} { return new(calculatorpb.CalculatorStreamingResponse) },
+		Handle: func(resp interface{}) error {
+			fmt.Printf("New max of %v\n", resp.(*calculatorpb.CalculatorStreamingResponse).GetX())
+			return nil
+		},
+	}
+	if *flowWindow > 0 {
+		cfg.Ack = func(resp interface{}) interface{} {
+			return &calculatorpb.CalculatorStreamingRequest{Ack: resp.(*calculatorpb.CalculatorStreamingResponse).GetSequence()}
+		}
+	}
+	session := bidi.NewSession(stream, cfg)
 
-	go func() {
-		for _, i := range requests {
-			req := &calculatorpb.CalculatorStreamingRequest{
-				X: i,
-			}
-			fmt.Println("Sending value of %v", req.GetX())
-			stream.Send(req)
-			time.Sleep(time.Second)
+	requests := []int32{1, 5, 3, 6, 2, 20}
+	for _, i := range requests {
+		req := &calculatorpb.CalculatorStreamingRequest{
+			X: i,
 		}
-		stream.CloseSend()
-	}()
-
-	go func() {
-		for {
-			res, err := stream.Recv()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				log.Fatalf("Closing connection due to %v", err)
-				break
-			}
-			fmt.Println("New max of %v\n", res.GetX())
+		fmt.Printf("Sending value of %v\n", req.GetX())
+		if err := session.Send(req); err != nil {
+			break
 		}
-		close(waitc)
-	}()
+		time.Sleep(time.Second)
+	}
+	session.CloseSend()
 
-	<-waitc
+	if err := session.Wait(); err != nil {
+		log.Fatalf("Closing connection due to %v", rpcerror.Describe(err))
+	}
 }
 
 func doErrorUnary(c calculatorpb.CalculatorServiceClient) {
@@ -155,10 +211,13 @@ func doErrorCall(c calculatorpb.CalculatorServiceClient, n int32) {
 		respErr, ok := status.FromError(err)
 		if ok {
 			// actual error from gRPC (user error)
-			fmt.Printf("Error from server: %v\n", respErr.Message())
-			fmt.Println(respErr.Code())
+			fmt.Printf("Error from server: %v\n", rpcerror.Describe(err))
+			if reason, _, _ := rpcerror.Reason(err); reason == "NEGATIVE_NUMBER" {
+				fmt.Println("We sent a negative number!")
+				return
+			}
 			if respErr.Code() == codes.InvalidArgument {
-				fmt.Println("We probably sent a negative number!")
+				fmt.Println("We sent an invalid argument!")
 				return
 			}
 		} else {
@@ -168,3 +227,109 @@ func doErrorCall(c calculatorpb.CalculatorServiceClient, n int32) {
 	}
 	fmt.Printf("Result of square root of %v is %v\n", n, resp.GetNumberRoot())
 }
+
+func doRoots(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting to do SquareRoot RPCs in every mode...")
+	requests := []*calculatorpb.SquareRootRequest{
+		{Value: 2, Precision: wrapperspb.Int32(4)},
+		{Number: -8, Degree: 3},
+		{Number: -4, Mode: calculatorpb.SquareRootRequest_MODE_COMPLEX},
+		{Value: -2.25, Degree: 4, Mode: calculatorpb.SquareRootRequest_MODE_COMPLEX, Precision: wrapperspb.Int32(6)},
+		{Number: 17, Mode: calculatorpb.SquareRootRequest_MODE_INTEGER},
+	}
+	for _, req := range requests {
+		resp, err := c.SquareRoot(context.Background(), req)
+		if err != nil {
+			fmt.Printf("Error from server: %v\n", rpcerror.Describe(err))
+			continue
+		}
+		fmt.Printf("Root of %v is %v\n", req, resp)
+	}
+}
+
+func doGetHistory(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting to page through the calculation history...")
+	req := &calculatorpb.GetHistoryRequest{PageSize: 20}
+	for {
+		resp, err := c.GetHistory(context.Background(), req)
+		if err != nil {
+			fmt.Printf("Error from server: %v\n", rpcerror.Describe(err))
+			return
+		}
+		for _, e := range resp.GetEntries() {
+			fmt.Printf("#%v %v %v by %v: %v -> %v %v\n", e.GetSequence(), e.GetTime().AsTime().Format(time.RFC3339),
+				e.GetMethod(), e.GetCaller(), e.GetRequests(), e.GetResponses(), e.GetCode())
+		}
+		if resp.GetNextPageToken() == "" {
+			return
+		}
+		req.PageToken = resp.GetNextPageToken()
+	}
+}
+
+func doBatch(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting to do a CalculateBatch RPC...")
+	req := &calculatorpb.CalculateBatchRequest{
+		Requests: []*calculatorpb.CalculatorRequest{
+			{X: 3, Y: 10},
+			{X: math.MaxInt32, Y: 1},
+			{X: -7, Y: 2},
+		},
+	}
+	res, err := c.CalculateBatch(context.Background(), req)
+	if err != nil {
+		fmt.Printf("Error from server: %v\n", rpcerror.Describe(err))
+		return
+	}
+	for i, r := range res.GetResults() {
+		x, y := req.GetRequests()[i].GetX(), req.GetRequests()[i].GetY()
+		if e := r.GetError(); e != nil {
+			fmt.Printf("%v + %v failed: %v (%v)\n", x, y, e.GetMessage(), e.GetReason())
+			continue
+		}
+		fmt.Printf("%v + %v = %v\n", x, y, r.GetResponse().GetSum())
+	}
+}
+
+func doCalculateStream(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting to do a CalculateStream RPC...")
+	ctx := context.Background()
+	if *parallelism > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-parallelism", strconv.Itoa(*parallelism))
+	}
+	stream, err := c.CalculateStream(ctx)
+	if err != nil {
+		log.Fatalf("Error starting to stream to server: %v", err)
+	}
+
+	// results come back as their jobs complete, matched by correlation ID
+	session := bidi.NewSession(stream, bidi.Config{
+		NewResponse: func() interface{} { return new(calculatorpb.CalculateStreamResponse) },
+		Handle: func(resp interface{}) error {
+			res := resp.(*calculatorpb.CalculateStreamResponse)
+			if e := res.GetError(); e != nil {
+				fmt.Printf("Job %v failed: %v\n", res.GetCorrelationId(), e.GetMessage())
+				return nil
+			}
+			fmt.Printf("Job %v: %v\n", res.GetCorrelationId(), res.GetResult())
+			return nil
+		},
+	})
+	jobs := []*calculatorpb.CalculateStreamRequest{
+		{CorrelationId: "factors", Operation: &calculatorpb.CalculateStreamRequest_PrimeFactors{PrimeFactors: 1277124}},
+		{CorrelationId: "sum", Operation: &calculatorpb.CalculateStreamRequest_Sum{Sum: &calculatorpb.CalculatorRequest{X: 3, Y: 10}}},
+		{CorrelationId: "root", Operation: &calculatorpb.CalculateStreamRequest_SquareRoot{SquareRoot: &calculatorpb.SquareRootRequest{Number: 10}}},
+		{CorrelationId: "average", Operation: &calculatorpb.CalculateStreamRequest_Average{Average: &calculatorpb.Numbers{Numbers: []int32{1, 2, 3, 4}}}},
+		{CorrelationId: "negative-root", Operation: &calculatorpb.CalculateStreamRequest_SquareRoot{SquareRoot: &calculatorpb.SquareRootRequest{Number: -1}}},
+	}
+	for _, job := range jobs {
+		if err := session.Send(job); err != nil {
+			break
+		}
+	}
+	session.CloseSend()
+
+	if err := session.Wait(); err != nil {
+		log.Fatalf("Closing connection due to %v", rpcerror.Describe(err))
+	}
+}
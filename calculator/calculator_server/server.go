@@ -1,109 +1,147 @@
 package main
 
 import (
-	"context"
-	"fmt"
+	"flag"
+	"go-grpc/audit"
+	"go-grpc/cache"
 	"go-grpc/calculator/calculatorpb"
+	"go-grpc/calculator/calculatorserver"
+	"go-grpc/deadline"
+	"go-grpc/fault"
+	"go-grpc/fault/faultpb"
+	"go-grpc/ratelimit"
+	"go-grpc/replay"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-	"io"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"log"
-	"math"
 	"net"
+	"net/http"
+	"runtime"
 )
 
-type server struct {
-}
+var (
+	addr           = flag.String("addr", "0.0.0.0:50051", "address to listen on")
+	auditLog       = flag.String("audit_log", "calculator_audit.log", "path of the audit log recording every call and served by GetHistory, empty to disable")
+	auditMaxBytes  = flag.Int64("audit_max_bytes", 10<<20, "size at which the audit log is rotated, 0 to never rotate")
+	auditMaxFiles  = flag.Int("audit_max_files", 5, "number of rotated audit log files to keep")
+	cacheSize      = flag.Int("cache_size", 10000, "maximum number of SquareRoot and CalculatePrimeStreaming results to cache, 0 to disable")
+	cacheTTL       = flag.Duration("cache_ttl", 0, "how long cached results are served, 0 to serve them until evicted")
+	debugAddr      = flag.String("debug_addr", "", "address to serve cache metrics on at /debug/vars, empty to disable")
+	batchWorkers   = flag.Int("batch_workers", runtime.NumCPU(), "number of additions of a CalculateBatch call performed concurrently")
+	maxBatchSize   = flag.Int("max_batch_size", 10000, "maximum number of additions in a CalculateBatch call")
+	parallelism    = flag.Int("stream_parallelism", runtime.NumCPU(), "number of jobs of a CalculateStream call performed concurrently, unless the call asks for another number")
+	maxParallelism = flag.Int("max_stream_parallelism", 64, "maximum number of jobs of a CalculateStream call performed concurrently")
+	rateLimits     = flag.String("rate_limits", "calculator/calculator_server/rate_limits.json", "path to the JSON per-method rate limit config, empty to disable")
+	deadlines      = flag.String("deadlines", "calculator/calculator_server/deadlines.json", "path to the JSON per-method default and maximum deadline config, empty to disable")
+	faultInjection = flag.Bool("fault_injection", false, "inject the faults of -faults into calls and serve the FaultService RPCs changing them, for testing clients")
+	faults         = flag.String("faults", "calculator/calculator_server/faults.json", "path to the JSON per-method fault config used by -fault_injection, empty to start without faults")
+	record         = flag.String("record", "", "path of a file to record every call to, for replaying against another server with replay_client, empty to disable")
+)
+
+func main() {
+	flag.Parse()
 
-func (*server) Calculate(ctx context.Context, r *calculatorpb.CalculatorRequest) (*calculatorpb.CalculatorResponse, error) {
-	fmt.Printf("Calculate the following: %v + %v", r.X, r.Y)
-	result := r.X + r.Y
-	rsp := calculatorpb.CalculatorResponse{
-		Sum: result,
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
 	}
-	return &rsp, nil
-}
 
-func (*server) CalculatePrimeStreaming(r *calculatorpb.CalculatorStreamingRequest, stream calculatorpb.CalculatorService_CalculatePrimeStreamingServer) error {
-	k := 2
-	N := int(r.GetX())
-	for N != 1 {
-		if N%k == 0 {
-			fmt.Printf("This is a factor: %v\n", k)
-			N = N / k
-			stream.Send(&calculatorpb.CalculatorStreamingResponse{
-				X: int32(k),
-			})
-		} else {
-			k = k + 1
-		}
+	srvOpts := calculatorserver.Options{
+		BatchWorkers:   *batchWorkers,
+		MaxBatchSize:   *maxBatchSize,
+		Parallelism:    *parallelism,
+		MaxParallelism: *maxParallelism,
+	}
+	if *cacheSize > 0 {
+		srvOpts.Cache = cache.New(*cacheSize, *cacheTTL)
+		srvOpts.Cache.Publish("calculator_cache")
+	}
+	if *debugAddr != "" {
+		// The cache package imports expvar, which serves /debug/vars on the
+		// default mux.
+		go func() {
+			log.Printf("Serving metrics on http://%v/debug/vars", *debugAddr)
+			if err := http.ListenAndServe(*debugAddr, nil); err != nil {
+				log.Printf("Failed to serve metrics: %v", err)
+			}
+		}()
 	}
-	return nil
-}
 
-func (*server) CalculateAverage(stream calculatorpb.CalculatorService_CalculateAverageServer) error {
-	fmt.Println("Getting a streaming client request.")
-	sum := 0
-	count := 0
-	for {
-		x, err := stream.Recv()
-		if err == io.EOF {
-			average := float64(sum) / float64(count)
-			return stream.SendAndClose(&calculatorpb.CalculatorAverageResponse{
-				X: average,
-			})
-		}
+	var opts []grpc.ServerOption
+	if *auditLog != "" {
+		l, err := audit.Open(*auditLog, *auditMaxBytes, *auditMaxFiles)
 		if err != nil {
-			log.Fatalf("Error with receiving client data: %v", err)
+			log.Fatalf("Failed to open audit log: %v", err)
 		}
-		sum += int(x.GetX())
-		count++
+		defer l.Close()
+		srvOpts.Audit = l
+		// Audit first so that calls rejected by the other interceptors are
+		// recorded too.
+		r := audit.NewRecorder(l, "/calculator.CalculatorService/GetHistory")
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(r.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(r.StreamInterceptor()),
+		)
 	}
-}
-
-func (*server) CalculateStreamingMax(stream calculatorpb.CalculatorService_CalculateStreamingMaxServer) error {
-	fmt.Println("Getting a BiDi client request")
-	max := int32(0)
-	for {
-		msg, err := stream.Recv()
-		if err == io.EOF {
-			return nil
+	if *record != "" {
+		rec, err := replay.Open(*record)
+		if err != nil {
+			log.Fatalf("Failed to open recording: %v", err)
 		}
+		defer rec.Close()
+		r := replay.NewRecorder(rec)
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(r.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(r.StreamInterceptor()),
+		)
+	}
+	if *rateLimits != "" {
+		cfg, err := ratelimit.LoadConfig(*rateLimits)
 		if err != nil {
-			log.Fatalf("Error receiving BiDi data from client: %v", err)
+			log.Fatalf("Failed to load rate limits: %v", err)
 		}
-		if msg.GetX() > max {
-			max = msg.GetX()
-			stream.Send(&calculatorpb.CalculatorStreamingResponse{
-				X: max,
-			})
+		l := ratelimit.New(cfg)
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(l.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(l.StreamInterceptor()),
+		)
+	}
+	if *deadlines != "" {
+		cfg, err := deadline.LoadConfig(*deadlines)
+		if err != nil {
+			log.Fatalf("Failed to load deadlines: %v", err)
 		}
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(deadline.UnaryInterceptor(cfg)),
+			grpc.ChainStreamInterceptor(deadline.StreamInterceptor(cfg)),
+		)
 	}
-}
-
-func (*server) SquareRoot(ctx context.Context, req *calculatorpb.SquareRootRequest) (*calculatorpb.SquareRootResponse, error) {
-	fmt.Println("Received SquareRoot RPC")
-	number := req.GetNumber()
-	if number < 0 {
-		return nil, status.Errorf(
-			codes.InvalidArgument,
-			fmt.Sprintf("Received a negative number: %v", number),
+	var injector *fault.Injector
+	if *faultInjection {
+		injector, err = fault.New(*faults)
+		if err != nil {
+			log.Fatalf("Failed to load faults: %v", err)
+		}
+		log.Printf("Fault injection enabled")
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(injector.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(injector.StreamInterceptor()),
 		)
 	}
-	return &calculatorpb.SquareRootResponse{
-		NumberRoot: math.Sqrt(float64(number)),
-	}, nil
-}
 
-func main() {
-	lis, err := net.Listen("tcp", "0.0.0.0:50051")
-	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
+	s := grpc.NewServer(opts...)
+	calculatorpb.RegisterCalculatorServiceServer(s, calculatorserver.New(srvOpts))
+	if injector != nil {
+		faultpb.RegisterFaultServiceServer(s, fault.NewAdmin(injector))
 	}
 
-	s := grpc.NewServer()
-	calculatorpb.RegisterCalculatorServiceServer(s, &server{})
+	// Clients load balancing across replicas use the health service to skip
+	// this one once it stops serving.
+	hs := health.NewServer()
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	hs.SetServingStatus("calculator.CalculatorService", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(s, hs)
 
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
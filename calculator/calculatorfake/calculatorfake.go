@@ -0,0 +1,119 @@
+// Package calculatorfake provides a scriptable fake of the calculator
+// service, for testing its clients.
+//
+// Every method fails with Unimplemented until it is scripted:
+//
+//	s := calculatorfake.New()
+//	s.On("CalculatePrimeStreaming").Return(
+//		&calculatorpb.CalculatorStreamingResponse{X: 2},
+//		&calculatorpb.CalculatorStreamingResponse{X: 5},
+//	).Delay(100 * time.Millisecond)
+//	c := harness.StartCalculator(t, s)
+package calculatorfake
+
+import (
+	"context"
+	"fmt"
+
+	"go-grpc/calculator/calculatorpb"
+	"go-grpc/fake"
+	"google.golang.org/protobuf/proto"
+)
+
+// Server is a fake calculator server.
+type Server struct {
+	calculatorpb.UnimplementedCalculatorServiceServer
+
+	methods map[string]*fake.Method
+}
+
+var _ calculatorpb.CalculatorServiceServer = (*Server)(nil)
+
+// New returns a fake calculator server whose methods are all unscripted.
+func New() *Server {
+	s := &Server{methods: make(map[string]*fake.Method)}
+	for _, name := range []string{
+		"Calculate",
+		"CalculatePrimeStreaming",
+		"CalculateAverage",
+		"CalculateStreamingMax",
+		"SquareRoot",
+		"CalculateBatch",
+		"CalculateStream",
+		"GetHistory",
+	} {
+		s.methods[name] = fake.NewMethod(name)
+	}
+	return s
+}
+
+// On returns the script of the method called name, such as "Calculate". It
+// panics if the service has no such method.
+func (s *Server) On(name string) *fake.Method {
+	m, ok := s.methods[name]
+	if !ok {
+		panic(fmt.Sprintf("calculatorfake: CalculatorService has no method %q", name))
+	}
+	return m
+}
+
+// Reset forgets the scripts and the calls of every method.
+func (s *Server) Reset() {
+	for _, m := range s.methods {
+		m.Reset()
+	}
+}
+
+func (s *Server) Calculate(ctx context.Context, req *calculatorpb.CalculatorRequest) (*calculatorpb.CalculatorResponse, error) {
+	res, err := s.On("Calculate").Unary(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*calculatorpb.CalculatorResponse), nil
+}
+
+func (s *Server) CalculatePrimeStreaming(req *calculatorpb.CalculatorStreamingRequest, stream calculatorpb.CalculatorService_CalculatePrimeStreamingServer) error {
+	return s.On("CalculatePrimeStreaming").ServerStream(req, stream)
+}
+
+func (s *Server) CalculateAverage(stream calculatorpb.CalculatorService_CalculateAverageServer) error {
+	return s.On("CalculateAverage").ClientStream(stream, func() proto.Message {
+		return new(calculatorpb.CalculatorStreamingRequest)
+	})
+}
+
+func (s *Server) CalculateStreamingMax(stream calculatorpb.CalculatorService_CalculateStreamingMaxServer) error {
+	return s.On("CalculateStreamingMax").BidiStream(stream, func() proto.Message {
+		return new(calculatorpb.CalculatorStreamingRequest)
+	})
+}
+
+func (s *Server) SquareRoot(ctx context.Context, req *calculatorpb.SquareRootRequest) (*calculatorpb.SquareRootResponse, error) {
+	res, err := s.On("SquareRoot").Unary(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*calculatorpb.SquareRootResponse), nil
+}
+
+func (s *Server) CalculateBatch(ctx context.Context, req *calculatorpb.CalculateBatchRequest) (*calculatorpb.CalculateBatchResponse, error) {
+	res, err := s.On("CalculateBatch").Unary(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*calculatorpb.CalculateBatchResponse), nil
+}
+
+func (s *Server) CalculateStream(stream calculatorpb.CalculatorService_CalculateStreamServer) error {
+	return s.On("CalculateStream").BidiStream(stream, func() proto.Message {
+		return new(calculatorpb.CalculateStreamRequest)
+	})
+}
+
+func (s *Server) GetHistory(ctx context.Context, req *calculatorpb.GetHistoryRequest) (*calculatorpb.GetHistoryResponse, error) {
+	res, err := s.On("GetHistory").Unary(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*calculatorpb.GetHistoryResponse), nil
+}
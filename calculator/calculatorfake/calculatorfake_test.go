@@ -0,0 +1,76 @@
+package calculatorfake_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"go-grpc/calculator/calculatorfake"
+	"go-grpc/calculator/calculatorpb"
+	"go-grpc/harness"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCalculate(t *testing.T) {
+	s := calculatorfake.New()
+	s.On("Calculate").Return(&calculatorpb.CalculatorResponse{Sum: 42}).
+		FailCall(2, status.Error(codes.Unavailable, "down"))
+	c := harness.StartCalculator(t, s)
+
+	tests := []struct {
+		want int32
+		code codes.Code
+	}{
+		{42, codes.OK},
+		{0, codes.Unavailable},
+		{42, codes.OK},
+	}
+	for i, tt := range tests {
+		res, err := c.Calculate(context.Background(), &calculatorpb.CalculatorRequest{X: 1, Y: 2})
+		if status.Code(err) != tt.code {
+			t.Fatalf("call %d: Calculate() error = %v, want code %v", i+1, err, tt.code)
+		}
+		if got := res.GetSum(); got != tt.want {
+			t.Errorf("call %d: Calculate() = %v, want %v", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateStreamingMax(t *testing.T) {
+	s := calculatorfake.New()
+	s.On("CalculateStreamingMax").Return(
+		&calculatorpb.CalculatorStreamingResponse{X: 7},
+		&calculatorpb.CalculatorStreamingResponse{X: 9},
+	)
+	c := harness.StartCalculator(t, s)
+
+	stream, err := c.CalculateStreamingMax(context.Background())
+	if err != nil {
+		t.Fatalf("CalculateStreamingMax() error = %v", err)
+	}
+	for _, x := range []int32{1, 2, 3} {
+		if err := stream.Send(&calculatorpb.CalculatorStreamingRequest{X: x}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+	stream.CloseSend()
+	var got []int32
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		got = append(got, res.GetX())
+	}
+	if len(got) != 2 || got[0] != 7 || got[1] != 9 {
+		t.Errorf("CalculateStreamingMax() = %v, want [7 9]", got)
+	}
+	calls := s.On("CalculateStreamingMax").Calls()
+	if len(calls) != 1 || len(calls[0].Requests) != 3 {
+		t.Errorf("Calls() = %v, want 1 call of 3 requests", calls)
+	}
+}
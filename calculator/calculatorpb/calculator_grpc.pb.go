@@ -0,0 +1,503 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.4
+// source: calculator/calculatorpb/calculator.proto
+
+package calculatorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// CalculatorServiceClient is the client API for CalculatorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CalculatorServiceClient interface {
+	// Unary
+	Calculate(ctx context.Context, in *CalculatorRequest, opts ...grpc.CallOption) (*CalculatorResponse, error)
+	CalculatePrimeStreaming(ctx context.Context, in *CalculatorStreamingRequest, opts ...grpc.CallOption) (CalculatorService_CalculatePrimeStreamingClient, error)
+	CalculateAverage(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_CalculateAverageClient, error)
+	CalculateStreamingMax(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_CalculateStreamingMaxClient, error)
+	// error handling
+	// this RPC will throw an exception if the sent number is negative
+	// The error being sent is of type INVALID_ARGUMENT
+	SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error)
+	// adds many pairs of numbers at once. A failed addition does not fail the
+	// others, its error is returned in place of its result.
+	CalculateBatch(ctx context.Context, in *CalculateBatchRequest, opts ...grpc.CallOption) (*CalculateBatchResponse, error)
+	// performs the jobs streamed by the client concurrently, streaming back
+	// their results as they complete. The x-parallelism metadata sets how
+	// many jobs of the stream may run at once.
+	CalculateStream(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_CalculateStreamClient, error)
+	// pages through the audit log of the calls this server handled, oldest first
+	GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error)
+}
+
+type calculatorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCalculatorServiceClient(cc grpc.ClientConnInterface) CalculatorServiceClient {
+	return &calculatorServiceClient{cc}
+}
+
+func (c *calculatorServiceClient) Calculate(ctx context.Context, in *CalculatorRequest, opts ...grpc.CallOption) (*CalculatorResponse, error) {
+	out := new(CalculatorResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Calculate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) CalculatePrimeStreaming(ctx context.Context, in *CalculatorStreamingRequest, opts ...grpc.CallOption) (CalculatorService_CalculatePrimeStreamingClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[0], "/calculator.CalculatorService/CalculatePrimeStreaming", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceCalculatePrimeStreamingClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CalculatorService_CalculatePrimeStreamingClient interface {
+	Recv() (*CalculatorStreamingResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceCalculatePrimeStreamingClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceCalculatePrimeStreamingClient) Recv() (*CalculatorStreamingResponse, error) {
+	m := new(CalculatorStreamingResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) CalculateAverage(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_CalculateAverageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[1], "/calculator.CalculatorService/CalculateAverage", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceCalculateAverageClient{stream}
+	return x, nil
+}
+
+type CalculatorService_CalculateAverageClient interface {
+	Send(*CalculatorStreamingRequest) error
+	CloseAndRecv() (*CalculatorAverageResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceCalculateAverageClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceCalculateAverageClient) Send(m *CalculatorStreamingRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceCalculateAverageClient) CloseAndRecv() (*CalculatorAverageResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(CalculatorAverageResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) CalculateStreamingMax(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_CalculateStreamingMaxClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[2], "/calculator.CalculatorService/CalculateStreamingMax", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceCalculateStreamingMaxClient{stream}
+	return x, nil
+}
+
+type CalculatorService_CalculateStreamingMaxClient interface {
+	Send(*CalculatorStreamingRequest) error
+	Recv() (*CalculatorStreamingResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceCalculateStreamingMaxClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceCalculateStreamingMaxClient) Send(m *CalculatorStreamingRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceCalculateStreamingMaxClient) Recv() (*CalculatorStreamingResponse, error) {
+	m := new(CalculatorStreamingResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error) {
+	out := new(SquareRootResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/SquareRoot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) CalculateBatch(ctx context.Context, in *CalculateBatchRequest, opts ...grpc.CallOption) (*CalculateBatchResponse, error) {
+	out := new(CalculateBatchResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/CalculateBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) CalculateStream(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_CalculateStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[3], "/calculator.CalculatorService/CalculateStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceCalculateStreamClient{stream}
+	return x, nil
+}
+
+type CalculatorService_CalculateStreamClient interface {
+	Send(*CalculateStreamRequest) error
+	Recv() (*CalculateStreamResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceCalculateStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceCalculateStreamClient) Send(m *CalculateStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceCalculateStreamClient) Recv() (*CalculateStreamResponse, error) {
+	m := new(CalculateStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error) {
+	out := new(GetHistoryResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/GetHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CalculatorServiceServer is the server API for CalculatorService service.
+// All implementations must embed UnimplementedCalculatorServiceServer
+// for forward compatibility
+type CalculatorServiceServer interface {
+	// Unary
+	Calculate(context.Context, *CalculatorRequest) (*CalculatorResponse, error)
+	CalculatePrimeStreaming(*CalculatorStreamingRequest, CalculatorService_CalculatePrimeStreamingServer) error
+	CalculateAverage(CalculatorService_CalculateAverageServer) error
+	CalculateStreamingMax(CalculatorService_CalculateStreamingMaxServer) error
+	// error handling
+	// this RPC will throw an exception if the sent number is negative
+	// The error being sent is of type INVALID_ARGUMENT
+	SquareRoot(context.Context, *SquareRootRequest) (*SquareRootResponse, error)
+	// adds many pairs of numbers at once. A failed addition does not fail the
+	// others, its error is returned in place of its result.
+	CalculateBatch(context.Context, *CalculateBatchRequest) (*CalculateBatchResponse, error)
+	// performs the jobs streamed by the client concurrently, streaming back
+	// their results as they complete. The x-parallelism metadata sets how
+	// many jobs of the stream may run at once.
+	CalculateStream(CalculatorService_CalculateStreamServer) error
+	// pages through the audit log of the calls this server handled, oldest first
+	GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error)
+	mustEmbedUnimplementedCalculatorServiceServer()
+}
+
+// UnimplementedCalculatorServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedCalculatorServiceServer struct {
+}
+
+func (UnimplementedCalculatorServiceServer) Calculate(context.Context, *CalculatorRequest) (*CalculatorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Calculate not implemented")
+}
+func (UnimplementedCalculatorServiceServer) CalculatePrimeStreaming(*CalculatorStreamingRequest, CalculatorService_CalculatePrimeStreamingServer) error {
+	return status.Errorf(codes.Unimplemented, "method CalculatePrimeStreaming not implemented")
+}
+func (UnimplementedCalculatorServiceServer) CalculateAverage(CalculatorService_CalculateAverageServer) error {
+	return status.Errorf(codes.Unimplemented, "method CalculateAverage not implemented")
+}
+func (UnimplementedCalculatorServiceServer) CalculateStreamingMax(CalculatorService_CalculateStreamingMaxServer) error {
+	return status.Errorf(codes.Unimplemented, "method CalculateStreamingMax not implemented")
+}
+func (UnimplementedCalculatorServiceServer) SquareRoot(context.Context, *SquareRootRequest) (*SquareRootResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SquareRoot not implemented")
+}
+func (UnimplementedCalculatorServiceServer) CalculateBatch(context.Context, *CalculateBatchRequest) (*CalculateBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CalculateBatch not implemented")
+}
+func (UnimplementedCalculatorServiceServer) CalculateStream(CalculatorService_CalculateStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method CalculateStream not implemented")
+}
+func (UnimplementedCalculatorServiceServer) GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
+func (UnimplementedCalculatorServiceServer) mustEmbedUnimplementedCalculatorServiceServer() {}
+
+// UnsafeCalculatorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CalculatorServiceServer will
+// result in compilation errors.
+type UnsafeCalculatorServiceServer interface {
+	mustEmbedUnimplementedCalculatorServiceServer()
+}
+
+func RegisterCalculatorServiceServer(s grpc.ServiceRegistrar, srv CalculatorServiceServer) {
+	s.RegisterService(&CalculatorService_ServiceDesc, srv)
+}
+
+func _CalculatorService_Calculate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculatorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).Calculate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/Calculate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).Calculate(ctx, req.(*CalculatorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_CalculatePrimeStreaming_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CalculatorStreamingRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CalculatorServiceServer).CalculatePrimeStreaming(m, &calculatorServiceCalculatePrimeStreamingServer{stream})
+}
+
+type CalculatorService_CalculatePrimeStreamingServer interface {
+	Send(*CalculatorStreamingResponse) error
+	grpc.ServerStream
+}
+
+type calculatorServiceCalculatePrimeStreamingServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceCalculatePrimeStreamingServer) Send(m *CalculatorStreamingResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CalculatorService_CalculateAverage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).CalculateAverage(&calculatorServiceCalculateAverageServer{stream})
+}
+
+type CalculatorService_CalculateAverageServer interface {
+	SendAndClose(*CalculatorAverageResponse) error
+	Recv() (*CalculatorStreamingRequest, error)
+	grpc.ServerStream
+}
+
+type calculatorServiceCalculateAverageServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceCalculateAverageServer) SendAndClose(m *CalculatorAverageResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calculatorServiceCalculateAverageServer) Recv() (*CalculatorStreamingRequest, error) {
+	m := new(CalculatorStreamingRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CalculatorService_CalculateStreamingMax_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).CalculateStreamingMax(&calculatorServiceCalculateStreamingMaxServer{stream})
+}
+
+type CalculatorService_CalculateStreamingMaxServer interface {
+	Send(*CalculatorStreamingResponse) error
+	Recv() (*CalculatorStreamingRequest, error)
+	grpc.ServerStream
+}
+
+type calculatorServiceCalculateStreamingMaxServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceCalculateStreamingMaxServer) Send(m *CalculatorStreamingResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calculatorServiceCalculateStreamingMaxServer) Recv() (*CalculatorStreamingRequest, error) {
+	m := new(CalculatorStreamingRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CalculatorService_SquareRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SquareRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).SquareRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/SquareRoot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).SquareRoot(ctx, req.(*SquareRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_CalculateBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculateBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).CalculateBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/CalculateBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).CalculateBatch(ctx, req.(*CalculateBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_CalculateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).CalculateStream(&calculatorServiceCalculateStreamServer{stream})
+}
+
+type CalculatorService_CalculateStreamServer interface {
+	Send(*CalculateStreamResponse) error
+	Recv() (*CalculateStreamRequest, error)
+	grpc.ServerStream
+}
+
+type calculatorServiceCalculateStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceCalculateStreamServer) Send(m *CalculateStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calculatorServiceCalculateStreamServer) Recv() (*CalculateStreamRequest, error) {
+	m := new(CalculateStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CalculatorService_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/GetHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).GetHistory(ctx, req.(*GetHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CalculatorService_ServiceDesc is the grpc.ServiceDesc for CalculatorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CalculatorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "calculator.CalculatorService",
+	HandlerType: (*CalculatorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Calculate",
+			Handler:    _CalculatorService_Calculate_Handler,
+		},
+		{
+			MethodName: "SquareRoot",
+			Handler:    _CalculatorService_SquareRoot_Handler,
+		},
+		{
+			MethodName: "CalculateBatch",
+			Handler:    _CalculatorService_CalculateBatch_Handler,
+		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _CalculatorService_GetHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CalculatePrimeStreaming",
+			Handler:       _CalculatorService_CalculatePrimeStreaming_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "CalculateAverage",
+			Handler:       _CalculatorService_CalculateAverage_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "CalculateStreamingMax",
+			Handler:       _CalculatorService_CalculateStreamingMax_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "CalculateStream",
+			Handler:       _CalculatorService_CalculateStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "calculator/calculatorpb/calculator.proto",
+}
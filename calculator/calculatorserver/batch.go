@@ -0,0 +1,61 @@
+package calculatorserver
+
+import (
+	"context"
+	"fmt"
+	"go-grpc/calculator/calculatorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+)
+
+func (s *Server) CalculateBatch(ctx context.Context, req *calculatorpb.CalculateBatchRequest) (*calculatorpb.CalculateBatchResponse, error) {
+	requests := req.GetRequests()
+	fmt.Printf("Calculating a batch of %v additions\n", len(requests))
+	if len(requests) > s.maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "A batch may hold at most %v additions, got %v", s.maxBatchSize, len(requests))
+	}
+
+	// Every worker writes the results of the requests it takes at their
+	// index, which keeps them in order.
+	results := make([]*calculatorpb.CalculateBatchResult, len(requests))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < s.batchWorkers && w < len(requests); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = batchResult(calculate(requests[i]))
+			}
+		}()
+	}
+
+	var err error
+feed:
+	for i := range requests {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			err = status.FromContextError(ctx.Err()).Err()
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.CalculateBatchResponse{Results: results}, nil
+}
+
+func batchResult(res *calculatorpb.CalculatorResponse, err error) *calculatorpb.CalculateBatchResult {
+	if err != nil {
+		return &calculatorpb.CalculateBatchResult{
+			Result: &calculatorpb.CalculateBatchResult_Error{Error: calculationError(err)},
+		}
+	}
+	return &calculatorpb.CalculateBatchResult{
+		Result: &calculatorpb.CalculateBatchResult_Response{Response: res},
+	}
+}
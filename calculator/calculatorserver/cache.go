@@ -0,0 +1,67 @@
+package calculatorserver
+
+import (
+	"fmt"
+	"go-grpc/calculator/calculatorpb"
+	"google.golang.org/protobuf/proto"
+)
+
+// cacheKey identifies a request to method by its content. Deterministic
+// marshalling makes equal requests produce equal keys.
+func cacheKey(method string, req proto.Message) (string, bool) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", false
+	}
+	return method + "/" + string(b), true
+}
+
+// cachedSquareRoot returns the response cached for req, if any.
+func (s *Server) cachedSquareRoot(req *calculatorpb.SquareRootRequest) (*calculatorpb.SquareRootResponse, bool) {
+	if s.cache == nil {
+		return nil, false
+	}
+	key, ok := cacheKey("SquareRoot", req)
+	if !ok {
+		return nil, false
+	}
+	v, ok := s.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	// Responses are cloned on the way in and out so that no two calls share
+	// a message.
+	return proto.Clone(v.(*calculatorpb.SquareRootResponse)).(*calculatorpb.SquareRootResponse), true
+}
+
+func (s *Server) cacheSquareRoot(req *calculatorpb.SquareRootRequest, resp *calculatorpb.SquareRootResponse) {
+	if s.cache == nil {
+		return
+	}
+	if key, ok := cacheKey("SquareRoot", req); ok {
+		s.cache.Add(key, proto.Clone(resp))
+	}
+}
+
+// cachedFactors returns the prime factors cached for x, if any.
+func (s *Server) cachedFactors(x int32) ([]int32, bool) {
+	if s.cache == nil {
+		return nil, false
+	}
+	v, ok := s.cache.Get(factorsKey(x))
+	if !ok {
+		return nil, false
+	}
+	return v.([]int32), true
+}
+
+func (s *Server) cacheFactors(x int32, factors []int32) {
+	if s.cache == nil {
+		return
+	}
+	s.cache.Add(factorsKey(x), factors)
+}
+
+func factorsKey(x int32) string {
+	return fmt.Sprintf("CalculatePrimeStreaming/%d", x)
+}
@@ -0,0 +1,63 @@
+package calculatorserver
+
+import (
+	"fmt"
+	"go-grpc/calculator/calculatorpb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"strings"
+)
+
+const (
+	// errorDomain is the google.rpc.ErrorInfo domain of calculator errors.
+	errorDomain = "calculator.CalculatorService"
+	// reasonPrefix starts the name of every ErrorReason value and is left
+	// out of ErrorInfo reasons.
+	reasonPrefix = "ERROR_REASON_"
+)
+
+// calcError builds a status error carrying an ErrorInfo with the given reason
+// and metadata and, if field is not empty, a BadRequest naming the offending
+// field.
+func calcError(code codes.Code, reason calculatorpb.ErrorReason, field string, metadata map[string]string, format string, a ...interface{}) error {
+	msg := fmt.Sprintf(format, a...)
+	details := []protoiface.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason:   strings.TrimPrefix(reason.String(), reasonPrefix),
+			Domain:   errorDomain,
+			Metadata: metadata,
+		},
+	}
+	if field != "" {
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: field, Description: msg},
+			},
+		})
+	}
+
+	st := status.New(code, msg)
+	if d, err := st.WithDetails(details...); err == nil {
+		st = d
+	}
+	return st.Err()
+}
+
+// calculationError describes err for a client that performed several
+// calculations in one call.
+func calculationError(err error) *calculatorpb.CalculationError {
+	st := status.Convert(err)
+	e := &calculatorpb.CalculationError{
+		Code:    int32(st.Code()),
+		Message: st.Message(),
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.GetDomain() == errorDomain {
+			e.Reason = calculatorpb.ErrorReason(calculatorpb.ErrorReason_value[reasonPrefix+info.GetReason()])
+			e.Metadata = info.GetMetadata()
+		}
+	}
+	return e
+}
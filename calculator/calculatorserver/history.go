@@ -0,0 +1,69 @@
+package calculatorserver
+
+import (
+	"context"
+	"encoding/json"
+	"go-grpc/audit"
+	"go-grpc/calculator/calculatorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"log"
+)
+
+const (
+	// defaultPageSize is used by GetHistory when the request sets none.
+	defaultPageSize = 100
+	// maxPageSize bounds the page size GetHistory accepts.
+	maxPageSize = 1000
+)
+
+func (s *Server) GetHistory(ctx context.Context, req *calculatorpb.GetHistoryRequest) (*calculatorpb.GetHistoryResponse, error) {
+	if s.audit == nil {
+		return nil, status.Error(codes.FailedPrecondition, "Auditing is disabled on this server")
+	}
+	size := int(req.GetPageSize())
+	if size < 0 || size > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "Page size must be between 1 and %v, got %v", maxPageSize, size)
+	}
+	if size == 0 {
+		size = defaultPageSize
+	}
+
+	entries, next, err := s.audit.Page(req.GetPageToken(), size, req.GetMethod())
+	if err == audit.ErrInvalidPageToken {
+		return nil, status.Error(codes.InvalidArgument, "Invalid page token")
+	}
+	if err != nil {
+		// The error names files of the server, which are none of the
+		// client's business.
+		log.Printf("Reading the audit log: %v", err)
+		return nil, status.Error(codes.Internal, "Could not read the audit log")
+	}
+	res := &calculatorpb.GetHistoryResponse{NextPageToken: next}
+	for _, e := range entries {
+		res.Entries = append(res.Entries, &calculatorpb.AuditEntry{
+			Sequence:  e.Sequence,
+			Time:      timestamppb.New(e.Time),
+			Duration:  durationpb.New(e.Duration),
+			Method:    e.Method,
+			Caller:    e.Caller,
+			Peer:      e.Peer,
+			Requests:  rawStrings(e.Requests),
+			Responses: rawStrings(e.Responses),
+			Truncated: e.Truncated,
+			Code:      e.Code,
+			Message:   e.Message,
+		})
+	}
+	return res, nil
+}
+
+func rawStrings(msgs []json.RawMessage) []string {
+	var s []string
+	for _, m := range msgs {
+		s = append(s, string(m))
+	}
+	return s
+}
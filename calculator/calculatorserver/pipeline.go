@@ -0,0 +1,133 @@
+package calculatorserver
+
+import (
+	"context"
+	"go-grpc/bidi"
+	"go-grpc/calculator/calculatorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"strconv"
+)
+
+// parallelismKey is the metadata key naming how many jobs of a
+// CalculateStream call may run at once.
+const parallelismKey = "x-parallelism"
+
+func (s *Server) CalculateStream(stream calculatorpb.CalculatorService_CalculateStreamServer) error {
+	ctx := stream.Context()
+	n, err := s.streamParallelism(ctx)
+	if err != nil {
+		return err
+	}
+	reqs, recvErr := bidi.Receive(stream, func() interface{} {
+		return new(calculatorpb.CalculateStreamRequest)
+	})
+
+	// Jobs are only taken from the stream while fewer than n run, so a
+	// client sending faster than they complete is held back by the flow
+	// control of the stream.
+	results := make(chan *calculatorpb.CalculateStreamResponse)
+	running := 0
+	for {
+		if reqs == nil && running == 0 {
+			return nil
+		}
+		jobs := reqs
+		if running == n {
+			jobs = nil
+		}
+
+		select {
+		case req := <-jobs:
+			running++
+			go func(job *calculatorpb.CalculateStreamRequest) {
+				res := s.perform(ctx, job)
+				res.CorrelationId = job.GetCorrelationId()
+				select {
+				case results <- res:
+				case <-ctx.Done():
+				}
+			}(req.(*calculatorpb.CalculateStreamRequest))
+		case res := <-results:
+			running--
+			if err := stream.Send(res); err != nil {
+				return err
+			}
+		case err := <-recvErr:
+			if err != io.EOF {
+				return err
+			}
+			// Finish the running jobs before ending the stream.
+			reqs, recvErr = nil, nil
+		}
+	}
+}
+
+// perform runs job and returns its result, without correlation ID.
+func (s *Server) perform(ctx context.Context, job *calculatorpb.CalculateStreamRequest) *calculatorpb.CalculateStreamResponse {
+	var res calculatorpb.CalculateStreamResponse
+	var err error
+	switch op := job.GetOperation().(type) {
+	case *calculatorpb.CalculateStreamRequest_Sum:
+		var sum *calculatorpb.CalculatorResponse
+		if sum, err = calculate(op.Sum); err == nil {
+			res.Result = &calculatorpb.CalculateStreamResponse_Sum{Sum: sum}
+		}
+
+	case *calculatorpb.CalculateStreamRequest_SquareRoot:
+		var root *calculatorpb.SquareRootResponse
+		if root, err = s.root(op.SquareRoot); err == nil {
+			res.Result = &calculatorpb.CalculateStreamResponse_SquareRoot{SquareRoot: root}
+		}
+
+	case *calculatorpb.CalculateStreamRequest_PrimeFactors:
+		factors := &calculatorpb.Numbers{}
+		err = s.factorise(ctx, op.PrimeFactors, func(k int32) error {
+			factors.Numbers = append(factors.Numbers, k)
+			return nil
+		})
+		if err == nil {
+			res.Result = &calculatorpb.CalculateStreamResponse_PrimeFactors{PrimeFactors: factors}
+		}
+
+	case *calculatorpb.CalculateStreamRequest_Average:
+		numbers := op.Average.GetNumbers()
+		if len(numbers) == 0 {
+			err = calcError(codes.InvalidArgument, calculatorpb.ErrorReason_ERROR_REASON_EMPTY_STREAM, "average", nil,
+				"Cannot average an empty list of numbers")
+			break
+		}
+		sum := 0
+		for _, x := range numbers {
+			sum += int(x)
+		}
+		res.Result = &calculatorpb.CalculateStreamResponse_Average{Average: &calculatorpb.CalculatorAverageResponse{
+			X: float64(sum) / float64(len(numbers)),
+		}}
+
+	default:
+		err = status.Error(codes.InvalidArgument, "The job has no operation")
+	}
+
+	if err != nil {
+		res.Result = &calculatorpb.CalculateStreamResponse_Error{Error: calculationError(err)}
+	}
+	return &res
+}
+
+// streamParallelism returns how many jobs of the incoming CalculateStream
+// call may run at once.
+func (s *Server) streamParallelism(ctx context.Context) (int, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	v := md.Get(parallelismKey)
+	if len(v) == 0 {
+		return s.parallelism, nil
+	}
+	n, err := strconv.Atoi(v[0])
+	if err != nil || n < 1 || n > s.maxParallelism {
+		return 0, status.Errorf(codes.InvalidArgument, "Invalid %v metadata %q: must be between 1 and %v", parallelismKey, v[0], s.maxParallelism)
+	}
+	return n, nil
+}
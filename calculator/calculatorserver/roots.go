@@ -0,0 +1,94 @@
+package calculatorserver
+
+import (
+	"math"
+)
+
+// maxPrecision is the largest number of decimal places results can be
+// rounded to. Doubles carry about 15 significant decimal digits.
+const maxPrecision = 15
+
+// maxDegree is the largest degree of a root. Integer roots of higher degrees
+// are 1 for every positive int64 anyway, and bounding the degree bounds the
+// work integerRoot does.
+const maxDegree = 63
+
+// realRoot returns the real nth root of x. Even roots of negative numbers
+// have no real value, which is reported by ok being false.
+func realRoot(x float64, n int) (root float64, ok bool) {
+	switch {
+	case x >= 0:
+		return nthRoot(x, n), true
+	case n%2 == 1:
+		return -nthRoot(-x, n), true
+	}
+	return 0, false
+}
+
+// nthRoot returns the nth root of a non-negative x, using the exact square and
+// cube root functions where they apply.
+func nthRoot(x float64, n int) float64 {
+	switch n {
+	case 2:
+		return math.Sqrt(x)
+	case 3:
+		return math.Cbrt(x)
+	}
+	return math.Pow(x, 1/float64(n))
+}
+
+// complexRoot returns the principal nth root of x, the one with the smallest
+// positive argument.
+func complexRoot(x float64, n int) (re, im float64) {
+	if x >= 0 {
+		return nthRoot(x, n), 0
+	}
+	r := nthRoot(-x, n)
+	if n == 2 {
+		// Avoid the rounding noise cos(π/2) would leave in the real part.
+		return 0, r
+	}
+	theta := math.Pi / float64(n)
+	return r * math.Cos(theta), r * math.Sin(theta)
+}
+
+// integerRoot returns the largest integer whose nth power does not exceed x,
+// along with the remainder x - root^n. x must not be negative.
+func integerRoot(x int64, n int) (root, remainder int64) {
+	root = int64(math.Pow(float64(x), 1/float64(n)))
+	// The float estimate can be off by one either way.
+	for root > 0 {
+		if _, ok := powAtMost(root, n, x); ok {
+			break
+		}
+		root--
+	}
+	for {
+		if _, ok := powAtMost(root+1, n, x); !ok {
+			break
+		}
+		root++
+	}
+	p, _ := powAtMost(root, n, x)
+	return root, x - p
+}
+
+// powAtMost returns x^n, reporting false instead as soon as the power
+// exceeds limit, so that it never overflows. x and limit must not be
+// negative.
+func powAtMost(x int64, n int, limit int64) (int64, bool) {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		if x != 0 && p > limit/x {
+			return 0, false
+		}
+		p *= x
+	}
+	return p, true
+}
+
+// round rounds x to the given number of decimal places.
+func round(x float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(x*scale) / scale
+}
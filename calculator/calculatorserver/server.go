@@ -0,0 +1,337 @@
+// Package calculatorserver implements calculatorpb.CalculatorService.
+package calculatorserver
+
+import (
+	"context"
+	"fmt"
+	"go-grpc/audit"
+	"go-grpc/bidi"
+	"go-grpc/cache"
+	"go-grpc/calculator/calculatorpb"
+	"go-grpc/resume"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"math"
+	"runtime"
+	"strconv"
+)
+
+// Options configure a Server. Zero values select the defaults.
+type Options struct {
+	// Audit is the log GetHistory reads, nil if auditing is disabled.
+	Audit *audit.Log
+	// Cache holds the results of SquareRoot and CalculatePrimeStreaming
+	// calls, nil to disable caching.
+	Cache *cache.Cache
+	// BatchWorkers is the number of additions of a CalculateBatch call
+	// performed concurrently, the number of CPUs by default.
+	BatchWorkers int
+	// MaxBatchSize is the maximum number of additions in a CalculateBatch
+	// call, 10000 by default.
+	MaxBatchSize int
+	// Parallelism is the number of jobs of a CalculateStream call performed
+	// concurrently unless the call asks for another number, the number of
+	// CPUs by default.
+	Parallelism int
+	// MaxParallelism bounds the parallelism a CalculateStream call may ask
+	// for, 64 by default.
+	MaxParallelism int
+}
+
+// Server implements calculatorpb.CalculatorServiceServer.
+type Server struct {
+	calculatorpb.UnimplementedCalculatorServiceServer
+
+	audit          *audit.Log
+	cache          *cache.Cache
+	batchWorkers   int
+	maxBatchSize   int
+	parallelism    int
+	maxParallelism int
+}
+
+// New returns a server configured by opts.
+func New(opts Options) *Server {
+	if opts.BatchWorkers < 1 {
+		opts.BatchWorkers = runtime.NumCPU()
+	}
+	if opts.MaxBatchSize == 0 {
+		opts.MaxBatchSize = 10000
+	}
+	if opts.MaxParallelism == 0 {
+		opts.MaxParallelism = 64
+	}
+	if opts.Parallelism < 1 {
+		opts.Parallelism = runtime.NumCPU()
+	}
+	if opts.Parallelism > opts.MaxParallelism {
+		opts.Parallelism = opts.MaxParallelism
+	}
+	return &Server{
+		audit:          opts.Audit,
+		cache:          opts.Cache,
+		batchWorkers:   opts.BatchWorkers,
+		maxBatchSize:   opts.MaxBatchSize,
+		parallelism:    opts.Parallelism,
+		maxParallelism: opts.MaxParallelism,
+	}
+}
+
+func (*Server) Calculate(ctx context.Context, r *calculatorpb.CalculatorRequest) (*calculatorpb.CalculatorResponse, error) {
+	fmt.Printf("Calculate the following: %v + %v", r.X, r.Y)
+	return calculate(r)
+}
+
+func calculate(r *calculatorpb.CalculatorRequest) (*calculatorpb.CalculatorResponse, error) {
+	result := int64(r.X) + int64(r.Y)
+	if result > math.MaxInt32 || result < math.MinInt32 {
+		return nil, calcError(codes.OutOfRange, calculatorpb.ErrorReason_ERROR_REASON_OVERFLOW, "",
+			map[string]string{"min": strconv.Itoa(math.MinInt32), "max": strconv.Itoa(math.MaxInt32)},
+			"The sum of %v and %v does not fit in an int32", r.X, r.Y)
+	}
+	rsp := calculatorpb.CalculatorResponse{
+		Sum: int32(result),
+	}
+	return &rsp, nil
+}
+
+func (s *Server) CalculatePrimeStreaming(r *calculatorpb.CalculatorStreamingRequest, stream calculatorpb.CalculatorService_CalculatePrimeStreamingServer) error {
+	// A resumed stream starts right after the last factor the client got.
+	sent, err := resume.Position(r)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Cannot resume CalculatePrimeStreaming: %v", err)
+	}
+	var seq uint64
+	return s.factorise(stream.Context(), r.GetX(), func(k int32) error {
+		seq++
+		if seq <= sent {
+			return nil
+		}
+		return stream.Send(&calculatorpb.CalculatorStreamingResponse{
+			X:           k,
+			Sequence:    seq,
+			ResumeToken: resume.Token(r, seq),
+		})
+	})
+}
+
+// factorise calls emit with every prime factor of x, in increasing order.
+func (s *Server) factorise(ctx context.Context, x int32, emit func(int32) error) error {
+	if x < 1 {
+		return calcError(codes.InvalidArgument, calculatorpb.ErrorReason_ERROR_REASON_NON_POSITIVE_NUMBER, "x",
+			map[string]string{"x": strconv.Itoa(int(x)), "min": "1"},
+			"Only positive numbers can be factorised, got %v", x)
+	}
+	if factors, ok := s.cachedFactors(x); ok {
+		fmt.Printf("Replaying cached factors of %v\n", x)
+		for _, k := range factors {
+			if err := emit(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var factors []int32
+	k := 2
+	N := int(x)
+	for N != 1 {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		default:
+		}
+		if N%k == 0 {
+			fmt.Printf("This is a factor: %v\n", k)
+			N = N / k
+			factors = append(factors, int32(k))
+			if err := emit(int32(k)); err != nil {
+				return err
+			}
+		} else {
+			k = k + 1
+		}
+	}
+	s.cacheFactors(x, factors)
+	return nil
+}
+
+func (*Server) CalculateAverage(stream calculatorpb.CalculatorService_CalculateAverageServer) error {
+	fmt.Println("Getting a streaming client request.")
+	sum := 0
+	count := 0
+	for {
+		x, err := stream.Recv()
+		if err == io.EOF {
+			if count == 0 {
+				return calcError(codes.InvalidArgument, calculatorpb.ErrorReason_ERROR_REASON_EMPTY_STREAM, "", nil,
+					"Cannot average an empty stream of numbers")
+			}
+			average := float64(sum) / float64(count)
+			return stream.SendAndClose(&calculatorpb.CalculatorAverageResponse{
+				X: average,
+			})
+		}
+		if err != nil {
+			return err
+		}
+		sum += int(x.GetX())
+		count++
+	}
+}
+
+func (*Server) CalculateStreamingMax(stream calculatorpb.CalculatorService_CalculateStreamingMaxServer) error {
+	fmt.Println("Getting a BiDi client request")
+	w, err := bidi.WindowFromContext(stream.Context())
+	if err != nil {
+		return err
+	}
+	reqs, recvErr := bidi.Receive(stream, func() interface{} {
+		return new(calculatorpb.CalculatorStreamingRequest)
+	})
+
+	max := int32(0)
+	var pending []int32
+	for {
+		// New maximums are queued while the window of the client is full.
+		for len(pending) > 0 && w.Open() {
+			err := stream.Send(&calculatorpb.CalculatorStreamingResponse{
+				X:        pending[0],
+				Sequence: w.Next(),
+			})
+			if err != nil {
+				return err
+			}
+			pending = pending[1:]
+		}
+		if reqs == nil && len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case req := <-reqs:
+			msg := req.(*calculatorpb.CalculatorStreamingRequest)
+			if msg.GetAck() != 0 {
+				if err := w.Ack(msg.GetAck()); err != nil {
+					return err
+				}
+				continue
+			}
+			if msg.GetX() > max {
+				max = msg.GetX()
+				if len(pending) == bidi.MaxQueued {
+					return status.Errorf(codes.ResourceExhausted, "More than %v maximums are waiting for acknowledgements", bidi.MaxQueued)
+				}
+				pending = append(pending, max)
+			}
+		case err := <-recvErr:
+			if err != io.EOF {
+				return err
+			}
+			// The client can no longer acknowledge anything.
+			w.Close()
+			reqs, recvErr = nil, nil
+		}
+	}
+}
+
+func (s *Server) SquareRoot(ctx context.Context, req *calculatorpb.SquareRootRequest) (*calculatorpb.SquareRootResponse, error) {
+	fmt.Println("Received SquareRoot RPC")
+	return s.root(req)
+}
+
+// root answers req from the cache if possible.
+func (s *Server) root(req *calculatorpb.SquareRootRequest) (*calculatorpb.SquareRootResponse, error) {
+	if resp, ok := s.cachedSquareRoot(req); ok {
+		return resp, nil
+	}
+	resp, err := squareRoot(req)
+	if err == nil {
+		s.cacheSquareRoot(req, resp)
+	}
+	return resp, err
+}
+
+func squareRoot(req *calculatorpb.SquareRootRequest) (*calculatorpb.SquareRootResponse, error) {
+	number := req.GetNumber()
+	x := float64(number)
+	if req.GetValue() != 0 {
+		if number != 0 {
+			return nil, calcError(codes.InvalidArgument, calculatorpb.ErrorReason_ERROR_REASON_CONFLICTING_FIELDS, "value", nil,
+				"Only one of number and value may be set")
+		}
+		x = req.GetValue()
+	}
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return nil, calcError(codes.InvalidArgument, calculatorpb.ErrorReason_ERROR_REASON_NON_FINITE_NUMBER, "value", nil,
+			"Received a non-finite number: %v", x)
+	}
+
+	degree := int(req.GetDegree())
+	if degree == 0 {
+		degree = 2
+	}
+	if degree < 2 || degree > maxDegree {
+		return nil, calcError(codes.InvalidArgument, calculatorpb.ErrorReason_ERROR_REASON_INVALID_DEGREE, "degree",
+			map[string]string{"degree": strconv.Itoa(degree), "min": "2", "max": strconv.Itoa(maxDegree)},
+			"The degree of a root must be between 2 and %v, got %v", maxDegree, degree)
+	}
+
+	places := -1
+	if p := req.GetPrecision(); p != nil {
+		places = int(p.GetValue())
+		if places < 0 || places > maxPrecision {
+			return nil, calcError(codes.InvalidArgument, calculatorpb.ErrorReason_ERROR_REASON_INVALID_PRECISION, "precision",
+				map[string]string{"precision": strconv.Itoa(places), "min": "0", "max": strconv.Itoa(maxPrecision)},
+				"Precision must be between 0 and %v decimal places, got %v", maxPrecision, places)
+		}
+	}
+	rounded := func(v float64) float64 {
+		if places < 0 {
+			return v
+		}
+		return round(v, places)
+	}
+
+	switch req.GetMode() {
+	case calculatorpb.SquareRootRequest_MODE_COMPLEX:
+		re, im := complexRoot(x, degree)
+		return &calculatorpb.SquareRootResponse{
+			NumberRoot: rounded(re),
+			Imaginary:  rounded(im),
+		}, nil
+
+	case calculatorpb.SquareRootRequest_MODE_INTEGER:
+		if req.GetValue() != 0 {
+			return nil, calcError(codes.InvalidArgument, calculatorpb.ErrorReason_ERROR_REASON_INTEGER_REQUIRED, "value", nil,
+				"Integer roots require an integer number, got %v", req.GetValue())
+		}
+		if number < 0 {
+			return nil, negativeNumberError("number", number)
+		}
+		root, remainder := integerRoot(int64(number), degree)
+		return &calculatorpb.SquareRootResponse{
+			NumberRoot:  float64(root),
+			IntegerRoot: root,
+			Remainder:   remainder,
+		}, nil
+	}
+
+	root, ok := realRoot(x, degree)
+	if !ok {
+		field := "number"
+		if req.GetValue() != 0 {
+			field = "value"
+		}
+		return nil, negativeNumberError(field, x)
+	}
+	return &calculatorpb.SquareRootResponse{
+		NumberRoot: rounded(root),
+	}, nil
+}
+
+func negativeNumberError(field string, number interface{}) error {
+	return calcError(codes.InvalidArgument, calculatorpb.ErrorReason_ERROR_REASON_NEGATIVE_NUMBER, field,
+		map[string]string{field: fmt.Sprint(number), "min": "0"},
+		"Received a negative number: %v", number)
+}
@@ -0,0 +1,502 @@
+package calculatorserver_test
+
+import (
+	"context"
+	"io"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"go-grpc/audit"
+	"go-grpc/calculator/calculatorpb"
+	"go-grpc/calculator/calculatorserver"
+	"go-grpc/harness"
+	"go-grpc/rpcerror"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestCalculate(t *testing.T) {
+	c := harness.StartCalculator(t, nil)
+	tests := []struct {
+		name string
+		x, y int32
+		want int32
+		code codes.Code
+	}{
+		{"positive", 3, 10, 13, codes.OK},
+		{"negative", -7, 2, -5, codes.OK},
+		{"overflow", math.MaxInt32, 1, 0, codes.OutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := c.Calculate(context.Background(), &calculatorpb.CalculatorRequest{X: tt.x, Y: tt.y})
+			if status.Code(err) != tt.code {
+				t.Fatalf("Calculate() error = %v, want code %v", err, tt.code)
+			}
+			if got := res.GetSum(); got != tt.want {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculatePrimeStreaming(t *testing.T) {
+	c := harness.StartCalculator(t, nil)
+	tests := []struct {
+		name string
+		x    int32
+		want []int32
+		code codes.Code
+	}{
+		{"one", 1, nil, codes.OK},
+		{"prime", 13, []int32{13}, codes.OK},
+		{"composite", 120, []int32{2, 2, 2, 3, 5}, codes.OK},
+		{"zero", 0, nil, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.CalculatePrimeStreaming(context.Background(), &calculatorpb.CalculatorStreamingRequest{X: tt.x})
+			if err != nil {
+				t.Fatalf("CalculatePrimeStreaming() error = %v", err)
+			}
+			got, err := recvAll(stream.Recv)
+			if status.Code(err) != tt.code {
+				t.Fatalf("Recv() error = %v, want code %v", err, tt.code)
+			}
+			if !equal(got, tt.want) {
+				t.Errorf("CalculatePrimeStreaming() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateAverage(t *testing.T) {
+	c := harness.StartCalculator(t, nil)
+	tests := []struct {
+		name    string
+		numbers []int32
+		want    float64
+		code    codes.Code
+		reason  string
+	}{
+		{"one", []int32{4}, 4, codes.OK, ""},
+		{"several", []int32{1, 2, 3, 4}, 2.5, codes.OK, ""},
+		{"empty stream", nil, 0, codes.InvalidArgument, "EMPTY_STREAM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.CalculateAverage(context.Background())
+			if err != nil {
+				t.Fatalf("CalculateAverage() error = %v", err)
+			}
+			for _, x := range tt.numbers {
+				if err := stream.Send(&calculatorpb.CalculatorStreamingRequest{X: x}); err != nil {
+					t.Fatalf("Send() error = %v", err)
+				}
+			}
+			res, err := stream.CloseAndRecv()
+			if status.Code(err) != tt.code {
+				t.Fatalf("CloseAndRecv() error = %v, want code %v", err, tt.code)
+			}
+			if reason, _, _ := rpcerror.Reason(err); reason != tt.reason {
+				t.Errorf("CloseAndRecv() error reason = %q, want %q", reason, tt.reason)
+			}
+			if got := res.GetX(); got != tt.want {
+				t.Errorf("CalculateAverage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateStreamingMax(t *testing.T) {
+	c := harness.StartCalculator(t, nil)
+	tests := []struct {
+		name    string
+		numbers []int32
+		want    []int32
+	}{
+		{"increasing", []int32{1, 2, 3}, []int32{1, 2, 3}},
+		{"mixed", []int32{1, 5, 3, 6, 2, 20}, []int32{1, 5, 6, 20}},
+		{"no positive number", []int32{-1, 0}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.CalculateStreamingMax(context.Background())
+			if err != nil {
+				t.Fatalf("CalculateStreamingMax() error = %v", err)
+			}
+			for _, x := range tt.numbers {
+				if err := stream.Send(&calculatorpb.CalculatorStreamingRequest{X: x}); err != nil {
+					t.Fatalf("Send() error = %v", err)
+				}
+			}
+			stream.CloseSend()
+			got, err := recvAll(stream.Recv)
+			if err != nil {
+				t.Fatalf("Recv() error = %v", err)
+			}
+			if !equal(got, tt.want) {
+				t.Errorf("CalculateStreamingMax() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSquareRoot(t *testing.T) {
+	c := harness.StartCalculator(t, nil)
+	tests := []struct {
+		name   string
+		req    *calculatorpb.SquareRootRequest
+		want   float64
+		code   codes.Code
+		reason string
+	}{
+		{"square", &calculatorpb.SquareRootRequest{Number: 16}, 4, codes.OK, ""},
+		{"zero", &calculatorpb.SquareRootRequest{}, 0, codes.OK, ""},
+		{"negative number", &calculatorpb.SquareRootRequest{Number: -4}, 0, codes.InvalidArgument, "NEGATIVE_NUMBER"},
+		{"negative value", &calculatorpb.SquareRootRequest{Value: -0.5}, 0, codes.InvalidArgument, "NEGATIVE_NUMBER"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := c.SquareRoot(context.Background(), tt.req)
+			if status.Code(err) != tt.code {
+				t.Fatalf("SquareRoot() error = %v, want code %v", err, tt.code)
+			}
+			if reason, _, _ := rpcerror.Reason(err); reason != tt.reason {
+				t.Errorf("SquareRoot() error reason = %q, want %q", reason, tt.reason)
+			}
+			if got := res.GetNumberRoot(); got != tt.want {
+				t.Errorf("SquareRoot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSquareRootInteger(t *testing.T) {
+	c := harness.StartCalculator(t, nil)
+	tests := []struct {
+		name      string
+		number    int32
+		degree    int32
+		root      int64
+		remainder int64
+		reason    string
+	}{
+		{"square", 17, 2, 4, 1, ""},
+		{"cube", 1000, 3, 10, 0, ""},
+		{"largest number", math.MaxInt32, 2, 46340, 88047, ""},
+		{"largest degree", 17, 63, 1, 16, ""},
+		{"largest degree of zero", 0, 63, 0, 0, ""},
+		{"largest degree of a power of two", 1 << 30, 30, 2, 0, ""},
+		{"degree over the maximum", 17, 64, 0, 0, "INVALID_DEGREE"},
+		{"huge degree", 17, math.MaxInt32, 0, 0, "INVALID_DEGREE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := c.SquareRoot(context.Background(), &calculatorpb.SquareRootRequest{
+				Number: tt.number,
+				Degree: tt.degree,
+				Mode:   calculatorpb.SquareRootRequest_MODE_INTEGER,
+			})
+			if reason, _, _ := rpcerror.Reason(err); reason != tt.reason {
+				t.Fatalf("SquareRoot() error = %v, want reason %q", err, tt.reason)
+			}
+			if tt.reason == "" && err != nil {
+				t.Fatalf("SquareRoot() error = %v", err)
+			}
+			if res.GetIntegerRoot() != tt.root || res.GetRemainder() != tt.remainder {
+				t.Errorf("SquareRoot() = %v remainder %v, want %v remainder %v",
+					res.GetIntegerRoot(), res.GetRemainder(), tt.root, tt.remainder)
+			}
+		})
+	}
+}
+
+func TestCalculateBatch(t *testing.T) {
+	c := harness.StartCalculator(t, calculatorserver.New(calculatorserver.Options{BatchWorkers: 4, MaxBatchSize: 100}))
+
+	// Results are in the order of the requests, failures in their place.
+	var reqs []*calculatorpb.CalculatorRequest
+	for i := int32(0); i < 100; i++ {
+		reqs = append(reqs, &calculatorpb.CalculatorRequest{X: i, Y: i})
+	}
+	reqs[42] = &calculatorpb.CalculatorRequest{X: math.MaxInt32, Y: 1}
+	res, err := c.CalculateBatch(context.Background(), &calculatorpb.CalculateBatchRequest{Requests: reqs})
+	if err != nil {
+		t.Fatalf("CalculateBatch() error = %v", err)
+	}
+	if len(res.GetResults()) != len(reqs) {
+		t.Fatalf("CalculateBatch() = %v results, want %v", len(res.GetResults()), len(reqs))
+	}
+	for i, r := range res.GetResults() {
+		if i == 42 {
+			e := r.GetError()
+			if codes.Code(e.GetCode()) != codes.OutOfRange || e.GetReason() != calculatorpb.ErrorReason_ERROR_REASON_OVERFLOW {
+				t.Errorf("result %v = %v, want an OutOfRange OVERFLOW error", i, r)
+			}
+			continue
+		}
+		if r.GetResponse() == nil || r.GetResponse().GetSum() != int32(2*i) {
+			t.Errorf("result %v = %v, want sum %v", i, r, 2*i)
+		}
+	}
+
+	_, err = c.CalculateBatch(context.Background(), &calculatorpb.CalculateBatchRequest{Requests: append(reqs, reqs[0])})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CalculateBatch() of 101 additions error = %v, want code %v", err, codes.InvalidArgument)
+	}
+
+	res, err = c.CalculateBatch(context.Background(), &calculatorpb.CalculateBatchRequest{})
+	if err != nil || len(res.GetResults()) != 0 {
+		t.Errorf("CalculateBatch() of no additions = %v, %v, want no results", res, err)
+	}
+}
+
+func TestGetHistory(t *testing.T) {
+	l, err := audit.Open(filepath.Join(t.TempDir(), "audit.log"), 0, 0)
+	if err != nil {
+		t.Fatalf("audit.Open() error = %v", err)
+	}
+	defer l.Close()
+	const getHistory = "/calculator.CalculatorService/GetHistory"
+	r := audit.NewRecorder(l, getHistory)
+	c := harness.StartCalculator(t, calculatorserver.New(calculatorserver.Options{Audit: l}),
+		grpc.ChainUnaryInterceptor(r.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(r.StreamInterceptor()),
+	)
+	ctx := context.Background()
+	for i := int32(0); i < 5; i++ {
+		if _, err := c.Calculate(ctx, &calculatorpb.CalculatorRequest{X: i, Y: 1}); err != nil {
+			t.Fatalf("Calculate() error = %v", err)
+		}
+	}
+	if _, err := c.SquareRoot(ctx, &calculatorpb.SquareRootRequest{Number: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("SquareRoot() error = %v, want code %v", err, codes.InvalidArgument)
+	}
+
+	// Pages of the Calculate calls, oldest first, each GetHistory call
+	// being recorded as well.
+	var got []int32
+	token := ""
+	for {
+		res, err := c.GetHistory(ctx, &calculatorpb.GetHistoryRequest{
+			PageSize:  2,
+			PageToken: token,
+			Method:    "/calculator.CalculatorService/Calculate",
+		})
+		if err != nil {
+			t.Fatalf("GetHistory() error = %v", err)
+		}
+		for _, e := range res.GetEntries() {
+			var req calculatorpb.CalculatorRequest
+			if len(e.GetRequests()) != 1 || protojson.Unmarshal([]byte(e.GetRequests()[0]), &req) != nil {
+				t.Fatalf("GetHistory() entry requests = %q, want one CalculatorRequest", e.GetRequests())
+			}
+			got = append(got, req.GetX())
+		}
+		if token = res.GetNextPageToken(); token == "" {
+			break
+		}
+	}
+	if want := []int32{0, 1, 2, 3, 4}; !equal(got, want) {
+		t.Errorf("GetHistory() requests of x = %v, want %v", got, want)
+	}
+
+	res, err := c.GetHistory(ctx, &calculatorpb.GetHistoryRequest{Method: "/calculator.CalculatorService/SquareRoot"})
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if e := res.GetEntries(); len(e) != 1 || e[0].GetCode() != "InvalidArgument" || e[0].GetSequence() != 6 {
+		t.Errorf("GetHistory() SquareRoot entries = %v, want the failed call", e)
+	}
+	res, err = c.GetHistory(ctx, &calculatorpb.GetHistoryRequest{Method: getHistory, PageSize: 1})
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if e := res.GetEntries(); len(e) != 1 || len(e[0].GetResponses()) != 0 {
+		t.Errorf("GetHistory() GetHistory entries = %v, want one without responses", e)
+	}
+
+	for _, req := range []*calculatorpb.GetHistoryRequest{
+		{PageSize: -1},
+		{PageSize: 1001},
+		{PageToken: "not a token"},
+	} {
+		if _, err := c.GetHistory(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("GetHistory(%v) error = %v, want code %v", req, err, codes.InvalidArgument)
+		}
+	}
+
+	disabled := harness.StartCalculator(t, nil)
+	if _, err := disabled.GetHistory(ctx, &calculatorpb.GetHistoryRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("GetHistory() without auditing error = %v, want code %v", err, codes.FailedPrecondition)
+	}
+}
+
+// slowPrime is a prime taking the server a while to factorise, so that jobs
+// factorising it finish well after jobs adding numbers.
+const slowPrime = 2999999
+
+func factorJob(id string) *calculatorpb.CalculateStreamRequest {
+	return &calculatorpb.CalculateStreamRequest{
+		CorrelationId: id,
+		Operation:     &calculatorpb.CalculateStreamRequest_PrimeFactors{PrimeFactors: slowPrime},
+	}
+}
+
+func sumJob(id string, x, y int32) *calculatorpb.CalculateStreamRequest {
+	return &calculatorpb.CalculateStreamRequest{
+		CorrelationId: id,
+		Operation:     &calculatorpb.CalculateStreamRequest_Sum{Sum: &calculatorpb.CalculatorRequest{X: x, Y: y}},
+	}
+}
+
+// calculateStream sends jobs on a CalculateStream call with the given
+// x-parallelism metadata, unless empty, and returns the results by
+// correlation ID along with the IDs in the order they arrived.
+func calculateStream(t *testing.T, c calculatorpb.CalculatorServiceClient, parallelism string, jobs ...*calculatorpb.CalculateStreamRequest) (map[string]*calculatorpb.CalculateStreamResponse, []string, error) {
+	t.Helper()
+	ctx := context.Background()
+	if parallelism != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-parallelism", parallelism)
+	}
+	stream, err := c.CalculateStream(ctx)
+	if err != nil {
+		t.Fatalf("CalculateStream() error = %v", err)
+	}
+	for _, job := range jobs {
+		if err := stream.Send(job); err != nil {
+			break
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend() error = %v", err)
+	}
+	results := make(map[string]*calculatorpb.CalculateStreamResponse)
+	var order []string
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return results, order, nil
+		}
+		if err != nil {
+			return results, order, err
+		}
+		results[res.GetCorrelationId()] = res
+		order = append(order, res.GetCorrelationId())
+	}
+}
+
+func TestCalculateStream(t *testing.T) {
+	c := harness.StartCalculator(t, nil)
+	results, order, err := calculateStream(t, c, "2", factorJob("factors"), sumJob("sum", 3, 10))
+	if err != nil {
+		t.Fatalf("CalculateStream() error = %v", err)
+	}
+	// The sum completes first although it was sent last.
+	if len(order) != 2 || order[0] != "sum" || order[1] != "factors" {
+		t.Fatalf("CalculateStream() results arrived as %q, want [sum factors]", order)
+	}
+	if got := results["sum"].GetSum().GetSum(); got != 13 {
+		t.Errorf("sum result = %v, want 13", got)
+	}
+	if got := results["factors"].GetPrimeFactors().GetNumbers(); len(got) != 1 || got[0] != slowPrime {
+		t.Errorf("factors result = %v, want [%v]", got, slowPrime)
+	}
+}
+
+// TestCalculateStreamParallelism checks that a job only starts once fewer
+// than x-parallelism jobs run, by sending a quick job after slow ones.
+func TestCalculateStreamParallelism(t *testing.T) {
+	tests := []struct {
+		parallelism string
+		// wantFirst holds the jobs whose result may arrive first.
+		wantFirst []string
+	}{
+		{"1", []string{"a"}},
+		{"2", []string{"a", "b"}},
+		{"3", []string{"sum"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.parallelism, func(t *testing.T) {
+			c := harness.StartCalculator(t, nil)
+			_, order, err := calculateStream(t, c, tt.parallelism, factorJob("a"), factorJob("b"), sumJob("sum", 1, 2))
+			if err != nil {
+				t.Fatalf("CalculateStream() error = %v", err)
+			}
+			if len(order) != 3 {
+				t.Fatalf("CalculateStream() results = %q, want 3", order)
+			}
+			ok := false
+			for _, id := range tt.wantFirst {
+				ok = ok || order[0] == id
+			}
+			if !ok {
+				t.Errorf("CalculateStream() results arrived as %q, want one of %q first", order, tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestCalculateStreamInvalidParallelism(t *testing.T) {
+	c := harness.StartCalculator(t, nil)
+	for _, parallelism := range []string{"0", "-1", "x", "65"} {
+		t.Run(parallelism, func(t *testing.T) {
+			_, _, err := calculateStream(t, c, parallelism, sumJob("sum", 1, 2))
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("CalculateStream() error = %v, want code %v", err, codes.InvalidArgument)
+			}
+		})
+	}
+}
+
+func TestCalculateStreamFailingJob(t *testing.T) {
+	c := harness.StartCalculator(t, nil)
+	root := &calculatorpb.CalculateStreamRequest{
+		CorrelationId: "root",
+		Operation:     &calculatorpb.CalculateStreamRequest_SquareRoot{SquareRoot: &calculatorpb.SquareRootRequest{Number: -4}},
+	}
+	// One job at a time, so the sum is only performed after the failure.
+	results, _, err := calculateStream(t, c, "1", root, sumJob("sum", 3, 10))
+	if err != nil {
+		t.Fatalf("CalculateStream() error = %v, want the stream to survive the failed job", err)
+	}
+	e := results["root"].GetError()
+	if codes.Code(e.GetCode()) != codes.InvalidArgument || e.GetReason() != calculatorpb.ErrorReason_ERROR_REASON_NEGATIVE_NUMBER {
+		t.Errorf("root result = %v, want an InvalidArgument NEGATIVE_NUMBER error", results["root"])
+	}
+	if got := results["sum"].GetSum().GetSum(); got != 13 {
+		t.Errorf("sum result = %v, want 13", results["sum"])
+	}
+}
+
+// recvAll receives the numbers of a stream until it ends.
+func recvAll(recv func() (*calculatorpb.CalculatorStreamingResponse, error)) ([]int32, error) {
+	var got []int32
+	for {
+		res, err := recv()
+		if err == io.EOF {
+			return got, nil
+		}
+		if err != nil {
+			return got, err
+		}
+		got = append(got, res.GetX())
+	}
+}
+
+func equal(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+// Package caller identifies the client behind an incoming RPC.
+package caller
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// IDKey is the metadata key callers use to identify themselves. Callers that
+// do not send it are identified by their peer address.
+//
+// The value is chosen by the client, so it names callers in logs but must
+// not be relied on to tell them apart unless a trusted proxy sets it.
+const IDKey = "x-client-id"
+
+// ID identifies the caller by its x-client-id metadata, falling back to the
+// host part of its peer address.
+func ID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(IDKey); len(ids) > 0 && ids[0] != "" {
+			return ids[0]
+		}
+	}
+	return Host(ctx)
+}
+
+// Host returns the host part of the peer address of the caller, which unlike
+// its ID cannot be changed by the caller at will.
+func Host(ctx context.Context) string {
+	addr := Addr(ctx)
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// Addr returns the peer address of the caller, or "unknown".
+func Addr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+	return p.Addr.String()
+}
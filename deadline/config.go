@@ -0,0 +1,82 @@
+package deadline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+// Duration is a time.Duration written in JSON as a Go duration string such
+// as "30s" or "2m".
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Limit holds the deadlines applied to one method. Zero values mean no
+// limit.
+type Limit struct {
+	// Default is the deadline given to calls whose client sent none.
+	Default Duration `json:"default"`
+	// Max caps the deadline of every call, including ones whose client
+	// asked for longer.
+	Max Duration `json:"max"`
+}
+
+// Config holds the deadlines of every method. Methods are keyed by their full
+// name, e.g. "/greet.GreetService/GreetManyTimes", and methods without an
+// entry use Default.
+type Config struct {
+	Default Limit            `json:"default"`
+	Methods map[string]Limit `json:"methods"`
+}
+
+// LoadConfig reads a JSON config file.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading deadline config: %v", err)
+	}
+	var c Config
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("parsing deadline config: %v", err)
+	}
+	if err := c.Default.validate(); err != nil {
+		return nil, fmt.Errorf("default: %v", err)
+	}
+	for m, l := range c.Methods {
+		if err := l.validate(); err != nil {
+			return nil, fmt.Errorf("%v: %v", m, err)
+		}
+	}
+	return &c, nil
+}
+
+func (c *Config) limit(method string) Limit {
+	if l, ok := c.Methods[method]; ok {
+		return l
+	}
+	return c.Default
+}
+
+func (l Limit) validate() error {
+	if l.Default < 0 || l.Max < 0 {
+		return fmt.Errorf("deadlines must not be negative")
+	}
+	if l.Max > 0 && l.Default > l.Max {
+		return fmt.Errorf("default deadline %v exceeds max %v", time.Duration(l.Default), time.Duration(l.Max))
+	}
+	return nil
+}
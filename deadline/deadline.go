@@ -0,0 +1,73 @@
+// Package deadline provides server interceptors that give every call a
+// deadline, so that handlers observing their context are guaranteed to give
+// up eventually even if the client never sets one.
+package deadline
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryInterceptor returns an interceptor applying cfg to unary RPCs.
+func UnaryInterceptor(cfg *Config) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, cancel := withDeadline(ctx, cfg.limit(info.FullMethod))
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor returns an interceptor applying cfg to streaming RPCs.
+// The stream ends with the error of the deadline as soon as it passes, even if
+// the handler is waiting for a message from the client. The handler is left to
+// give up on its own, its later calls on the stream failing.
+func StreamInterceptor(cfg *Config) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := withDeadline(ss.Context(), cfg.limit(info.FullMethod))
+		defer cancel()
+		if ctx == ss.Context() {
+			// gRPC itself ends the stream at the deadline of the client.
+			return handler(srv, ss)
+		}
+		done := make(chan error, 1)
+		go func() {
+			done <- handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		}()
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			// Returning ends the stream, which unblocks a handler waiting for
+			// a message.
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// withDeadline applies l to ctx. Calls without a deadline get l.Default and
+// calls whose deadline is further away than l.Max are cut down to it.
+func withDeadline(ctx context.Context, l Limit) (context.Context, context.CancelFunc) {
+	d, ok := ctx.Deadline()
+	switch {
+	case !ok && l.Default > 0:
+		return context.WithTimeout(ctx, time.Duration(l.Default))
+	case !ok && l.Max > 0:
+		return context.WithTimeout(ctx, time.Duration(l.Max))
+	case ok && l.Max > 0 && time.Until(d) > time.Duration(l.Max):
+		return context.WithTimeout(ctx, time.Duration(l.Max))
+	}
+	return ctx, func() {}
+}
+
+// serverStream replaces the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
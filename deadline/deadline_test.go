@@ -0,0 +1,60 @@
+package deadline_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-grpc/calculator/calculatorpb"
+	"go-grpc/deadline"
+	"go-grpc/harness"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestStreamWaitingForClient checks that the deadline ends a stream whose
+// handler waits for a client that never sends.
+func TestStreamWaitingForClient(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit deadline.Limit
+	}{
+		{"default", deadline.Limit{Default: deadline.Duration(100 * time.Millisecond)}},
+		{"max", deadline.Limit{Max: deadline.Duration(100 * time.Millisecond)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &deadline.Config{Default: tt.limit}
+			c := harness.StartCalculator(t, nil,
+				grpc.ChainUnaryInterceptor(deadline.UnaryInterceptor(cfg)),
+				grpc.ChainStreamInterceptor(deadline.StreamInterceptor(cfg)),
+			)
+			stream, err := c.CalculateAverage(context.Background())
+			if err != nil {
+				t.Fatalf("CalculateAverage() error = %v", err)
+			}
+			if err := stream.Send(&calculatorpb.CalculatorStreamingRequest{X: 1}); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			start := time.Now()
+			done := make(chan error, 1)
+			go func() {
+				// RecvMsg rather than CloseAndRecv, so that the client never
+				// finishes sending.
+				done <- stream.RecvMsg(new(calculatorpb.CalculatorAverageResponse))
+			}()
+			select {
+			case err := <-done:
+				if status.Code(err) != codes.DeadlineExceeded {
+					t.Errorf("RecvMsg() error = %v, want code %v", err, codes.DeadlineExceeded)
+				}
+				if d := time.Since(start); d > time.Second {
+					t.Errorf("RecvMsg() took %v, want about 100ms", d)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("RecvMsg() still waiting after 5s, want the deadline to end the stream")
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+// Package fake scripts the methods of fake gRPC servers and records the
+// calls they receive, so that clients can be tested without the real
+// servers. The fakes of each service, built on Method, are in the greetfake
+// and calculatorfake packages.
+package fake
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Call is a call received by a Method.
+type Call struct {
+	// Requests holds the request of a unary or server-streaming call, or
+	// every message the client streamed.
+	Requests []proto.Message
+	Metadata metadata.MD
+	// Err is the error the call ended with.
+	Err error
+}
+
+// Method scripts one method of a fake server. Its setters return the method
+// so that they can be chained, and may be called while the server runs.
+//
+// Unless scripted otherwise, calls fail with Unimplemented.
+type Method struct {
+	name string
+
+	mu        sync.Mutex
+	responses []proto.Message
+	err       error
+	failures  map[int]error
+	delay     time.Duration
+	started   int
+	calls     []Call
+}
+
+// NewMethod returns an unscripted method called name.
+func NewMethod(name string) *Method {
+	return &Method{name: name, failures: make(map[int]error)}
+}
+
+// Return scripts the responses of the method. Unary and client-streaming
+// calls get them one per call, the last one repeating, while streaming
+// calls get all of them, in order, on every call.
+func (m *Method) Return(responses ...proto.Message) *Method {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses = responses
+	return m
+}
+
+// Fail makes every call end with err, after sending the responses of
+// server-streaming and bidi calls. A nil err ends them successfully again.
+func (m *Method) Fail(err error) *Method {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+	return m
+}
+
+// FailCall makes the nth call, counting from 1, fail with err right away.
+func (m *Method) FailCall(n int, err error) *Method {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures[n] = err
+	return m
+}
+
+// Delay makes the method wait d before every response.
+func (m *Method) Delay(d time.Duration) *Method {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.delay = d
+	return m
+}
+
+// Calls returns the calls received so far, in the order they ended.
+func (m *Method) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// Reset forgets the script and the calls of the method.
+func (m *Method) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses, m.err, m.delay, m.started, m.calls = nil, nil, 0, 0, nil
+	m.failures = make(map[int]error)
+}
+
+// plan is the script of one call.
+type plan struct {
+	responses []proto.Message
+	err       error
+	delay     time.Duration
+	// n is the number of the call, counting from 1.
+	n int
+}
+
+// start begins a call, returning its plan. Calls are numbered when they
+// start, so the nth call is the nth to start.
+func (m *Method) start() plan {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.started++
+	p := plan{responses: m.responses, err: m.err, delay: m.delay, n: m.started}
+	if err, ok := m.failures[p.n]; ok {
+		p.responses, p.err = nil, err
+	}
+	if p.responses == nil && p.err == nil {
+		p.err = status.Errorf(codes.Unimplemented, "fake: no response scripted for %v", m.name)
+	}
+	return p
+}
+
+// finish records the end of a call and returns err.
+func (m *Method) finish(ctx context.Context, requests []proto.Message, err error) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Requests: requests, Metadata: md, Err: err})
+	return err
+}
+
+// wait sleeps for d, or until ctx is done.
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Unary handles a unary call.
+func (m *Method) Unary(ctx context.Context, req proto.Message) (proto.Message, error) {
+	p := m.start()
+	reqs := []proto.Message{req}
+	if p.responses == nil {
+		return nil, m.finish(ctx, reqs, p.err)
+	}
+	if err := wait(ctx, p.delay); err != nil {
+		return nil, m.finish(ctx, reqs, err)
+	}
+	if p.err != nil {
+		return nil, m.finish(ctx, reqs, p.err)
+	}
+	return pick(p), m.finish(ctx, reqs, nil)
+}
+
+// ServerStream handles a server-streaming call.
+func (m *Method) ServerStream(req proto.Message, stream grpc.ServerStream) error {
+	ctx := stream.Context()
+	p := m.start()
+	return m.finish(ctx, []proto.Message{req}, m.send(ctx, p, stream))
+}
+
+// ClientStream handles a client-streaming call, receiving messages made by
+// newRequest until the client is done.
+func (m *Method) ClientStream(stream grpc.ServerStream, newRequest func() proto.Message) error {
+	ctx := stream.Context()
+	p := m.start()
+	reqs, err := recvAll(stream, newRequest)
+	if err != nil {
+		return m.finish(ctx, reqs, err)
+	}
+	if p.responses == nil {
+		return m.finish(ctx, reqs, p.err)
+	}
+	if err := wait(ctx, p.delay); err != nil {
+		return m.finish(ctx, reqs, err)
+	}
+	if p.err != nil {
+		return m.finish(ctx, reqs, p.err)
+	}
+	return m.finish(ctx, reqs, stream.SendMsg(pick(p)))
+}
+
+// BidiStream handles a bidi call: the responses are sent while the messages
+// of the client, made by newRequest, are received.
+func (m *Method) BidiStream(stream grpc.ServerStream, newRequest func() proto.Message) error {
+	ctx := stream.Context()
+	p := m.start()
+	type result struct {
+		reqs []proto.Message
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		reqs, err := recvAll(stream, newRequest)
+		done <- result{reqs, err}
+	}()
+
+	if err := m.send(ctx, p, stream); err != nil {
+		// Returning ends the stream, which stops the receiving goroutine.
+		return m.finish(ctx, nil, err)
+	}
+	r := <-done
+	return m.finish(ctx, r.reqs, r.err)
+}
+
+// send sends the responses of p and returns the error it scripts.
+func (m *Method) send(ctx context.Context, p plan, stream grpc.ServerStream) error {
+	for _, res := range p.responses {
+		if err := wait(ctx, p.delay); err != nil {
+			return err
+		}
+		if err := stream.SendMsg(res); err != nil {
+			return err
+		}
+	}
+	return p.err
+}
+
+// pick returns the response of the unary or client-streaming call of p.
+func pick(p plan) proto.Message {
+	i := p.n - 1
+	if i >= len(p.responses) {
+		i = len(p.responses) - 1
+	}
+	return p.responses[i]
+}
+
+func recvAll(stream grpc.ServerStream, newRequest func() proto.Message) ([]proto.Message, error) {
+	var reqs []proto.Message
+	for {
+		req := newRequest()
+		err := stream.RecvMsg(req)
+		if err == io.EOF {
+			return reqs, nil
+		}
+		if err != nil {
+			return reqs, err
+		}
+		reqs = append(reqs, req)
+	}
+}
@@ -0,0 +1,105 @@
+package fault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go-grpc/fault/faultpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Admin serves the FaultService RPCs changing the faults of an Injector.
+type Admin struct {
+	faultpb.UnimplementedFaultServiceServer
+
+	in *Injector
+}
+
+var _ faultpb.FaultServiceServer = (*Admin)(nil)
+
+// NewAdmin returns an Admin of in.
+func NewAdmin(in *Injector) *Admin {
+	return &Admin{in: in}
+}
+
+func (a *Admin) GetFaults(ctx context.Context, req *faultpb.GetFaultsRequest) (*faultpb.GetFaultsResponse, error) {
+	return &faultpb.GetFaultsResponse{Faults: toProto(a.in.Config())}, nil
+}
+
+func (a *Admin) SetFaults(ctx context.Context, req *faultpb.SetFaultsRequest) (*faultpb.SetFaultsResponse, error) {
+	cfg, err := fromProto(req.GetFaults())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid faults: %v", err)
+	}
+	if err := a.in.Set(cfg); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid faults: %v", err)
+	}
+	return &faultpb.SetFaultsResponse{Faults: toProto(cfg)}, nil
+}
+
+func (a *Admin) ReloadFaults(ctx context.Context, req *faultpb.ReloadFaultsRequest) (*faultpb.ReloadFaultsResponse, error) {
+	cfg, err := a.in.Reload()
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "could not reload faults: %v", err)
+	}
+	return &faultpb.ReloadFaultsResponse{Faults: toProto(cfg)}, nil
+}
+
+func fromProto(p *faultpb.Faults) (*Config, error) {
+	cfg := &Config{Methods: make(map[string]Faults)}
+	for _, m := range p.GetMethods() {
+		if m.GetMethod() == "" {
+			return nil, fmt.Errorf("method name missing")
+		}
+		if _, ok := cfg.Methods[m.GetMethod()]; ok {
+			return nil, fmt.Errorf("%v: method given more than once", m.GetMethod())
+		}
+		var f Faults
+		if d := m.GetDelay(); d != nil {
+			f.Delay = &Delay{Duration: Duration(d.GetDuration().AsDuration()), Percent: d.GetPercent()}
+		}
+		for _, e := range m.GetErrors() {
+			f.Errors = append(f.Errors, Error{Code: codes.Code(e.GetCode()), Message: e.GetMessage(), Percent: e.GetPercent()})
+		}
+		if a := m.GetAbort(); a != nil {
+			f.Abort = &Abort{
+				AfterMessages: int(a.GetAfterMessages()),
+				Code:          codes.Code(a.GetCode()),
+				Message:       a.GetMessage(),
+				Percent:       a.GetPercent(),
+			}
+		}
+		cfg.Methods[m.GetMethod()] = f
+	}
+	return cfg, nil
+}
+
+func toProto(cfg *Config) *faultpb.Faults {
+	p := &faultpb.Faults{}
+	for method, f := range cfg.Methods {
+		m := &faultpb.MethodFaults{Method: method}
+		if d := f.Delay; d != nil {
+			m.Delay = &faultpb.Delay{Duration: durationpb.New(time.Duration(d.Duration)), Percent: d.Percent}
+		}
+		for _, e := range f.Errors {
+			m.Errors = append(m.Errors, &faultpb.Error{Code: uint32(e.Code), Message: e.Message, Percent: e.Percent})
+		}
+		if a := f.Abort; a != nil {
+			m.Abort = &faultpb.Abort{
+				AfterMessages: uint32(a.AfterMessages),
+				Code:          uint32(a.Code),
+				Message:       a.Message,
+				Percent:       a.Percent,
+			}
+		}
+		p.Methods = append(p.Methods, m)
+	}
+	sort.Slice(p.Methods, func(i, j int) bool {
+		return p.Methods[i].Method < p.Methods[j].Method
+	})
+	return p
+}
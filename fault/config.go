@@ -0,0 +1,140 @@
+package fault
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Duration is a time.Duration written in JSON as a Go duration string such
+// as "500ms" or "2s".
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Delay holds calls up before they are handled.
+type Delay struct {
+	Duration Duration `json:"duration"`
+	// Percent is the percentage of calls delayed, from 0 to 100.
+	Percent float64 `json:"percent"`
+}
+
+// Error fails calls before they are handled.
+type Error struct {
+	// Code is written in JSON either as a number or as the name of the code,
+	// e.g. "UNAVAILABLE".
+	Code codes.Code `json:"code"`
+	// Message is the message of the status, a default one if empty.
+	Message string  `json:"message"`
+	Percent float64 `json:"percent"`
+}
+
+// Abort fails streams part way through.
+type Abort struct {
+	// AfterMessages is the number of messages the server sends before the
+	// stream fails.
+	AfterMessages int        `json:"afterMessages"`
+	Code          codes.Code `json:"code"`
+	Message       string     `json:"message"`
+	Percent       float64    `json:"percent"`
+}
+
+// Faults are the faults injected into the calls of one method. Each of them
+// is drawn independently, except that at most one error is injected per
+// call.
+type Faults struct {
+	Delay  *Delay  `json:"delay"`
+	Errors []Error `json:"errors"`
+	// Abort is ignored for unary methods.
+	Abort *Abort `json:"abort"`
+}
+
+// Config holds the faults of every method. Methods are keyed by their full
+// name, e.g. "/greet.GreetService/GreetManyTimes", and methods without an
+// entry are left alone.
+type Config struct {
+	Methods map[string]Faults `json:"methods"`
+}
+
+// LoadConfig reads a JSON config file.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading fault config: %v", err)
+	}
+	var c Config
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("parsing fault config: %v", err)
+	}
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *Config) validate() error {
+	for m, f := range c.Methods {
+		if err := f.validate(); err != nil {
+			return fmt.Errorf("%v: %v", m, err)
+		}
+	}
+	return nil
+}
+
+func (f Faults) validate() error {
+	if d := f.Delay; d != nil {
+		if d.Duration < 0 {
+			return fmt.Errorf("delay must not be negative")
+		}
+		if err := validPercent(d.Percent); err != nil {
+			return fmt.Errorf("delay: %v", err)
+		}
+	}
+	var total float64
+	for _, e := range f.Errors {
+		if e.Code == codes.OK {
+			return fmt.Errorf("errors must not have code OK")
+		}
+		if err := validPercent(e.Percent); err != nil {
+			return fmt.Errorf("error %v: %v", e.Code, err)
+		}
+		total += e.Percent
+	}
+	if total > 100 {
+		return fmt.Errorf("error percentages add up to %v, over 100", total)
+	}
+	if a := f.Abort; a != nil {
+		if a.Code == codes.OK {
+			return fmt.Errorf("abort must not have code OK")
+		}
+		if a.AfterMessages < 0 {
+			return fmt.Errorf("abort must not be after a negative number of messages")
+		}
+		if err := validPercent(a.Percent); err != nil {
+			return fmt.Errorf("abort: %v", err)
+		}
+	}
+	return nil
+}
+
+func validPercent(p float64) error {
+	if p < 0 || p > 100 {
+		return fmt.Errorf("percent %v is not between 0 and 100", p)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+// Package fault provides server interceptors that inject faults into calls,
+// so that the resilience of clients can be tested against a real server.
+//
+// Calls of the configured methods may be delayed, failed with a status code
+// or, for streams, aborted after some messages, each with a configured
+// probability. The faults are held by an Injector, which can swap them at
+// runtime, either from its config file or over the FaultService admin RPCs.
+package fault
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Injector injects the faults of its Config. It is safe for concurrent use.
+type Injector struct {
+	path string
+
+	mu  sync.RWMutex
+	cfg *Config
+	// percent draws a number in [0, 100).
+	percent func() float64
+}
+
+// New returns an Injector whose faults are read from the JSON config file at
+// path, or which injects none until told to if path is empty.
+func New(path string) (*Injector, error) {
+	in := &Injector{
+		path: path,
+		cfg:  &Config{},
+		percent: func() float64 {
+			return rand.Float64() * 100
+		},
+	}
+	if path != "" {
+		if _, err := in.Reload(); err != nil {
+			return nil, err
+		}
+	}
+	return in, nil
+}
+
+// Config returns the faults being injected.
+func (in *Injector) Config() *Config {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	return in.cfg
+}
+
+// Set replaces the faults being injected by cfg, which must not be modified
+// afterwards.
+func (in *Injector) Set(cfg *Config) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.cfg = cfg
+	return nil
+}
+
+// Reload replaces the faults being injected by the ones of the config file,
+// leaving them alone if it cannot be read.
+func (in *Injector) Reload() (*Config, error) {
+	if in.path == "" {
+		return nil, fmt.Errorf("no fault config file to reload")
+	}
+	cfg, err := LoadConfig(in.path)
+	if err != nil {
+		return nil, err
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.cfg = cfg
+	return cfg, nil
+}
+
+// UnaryInterceptor returns an interceptor injecting the delays and errors of
+// unary methods.
+func (in *Injector) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		f, ok := in.faults(info.FullMethod)
+		if !ok {
+			return handler(ctx, req)
+		}
+		if err := in.before(ctx, f); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor returns an interceptor injecting the delays, errors and
+// aborts of streaming methods.
+func (in *Injector) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		f, ok := in.faults(info.FullMethod)
+		if !ok {
+			return handler(srv, ss)
+		}
+		if err := in.before(ss.Context(), f); err != nil {
+			return err
+		}
+		a := f.Abort
+		if a == nil || !in.draw(a.Percent) {
+			return handler(srv, ss)
+		}
+
+		err := newError(a.Code, a.Message, info.FullMethod, "aborted")
+		if a.AfterMessages == 0 {
+			return err
+		}
+		s := &abortingStream{
+			ServerStream: ss,
+			left:         a.AfterMessages,
+			err:          err,
+			abort:        make(chan struct{}),
+		}
+		done := make(chan error, 1)
+		go func() {
+			done <- handler(srv, s)
+		}()
+		select {
+		case err := <-done:
+			if s.aborted() {
+				// The handler may have returned the error of a send.
+				return s.err
+			}
+			return err
+		case <-s.abort:
+			// Returning ends the stream, so a handler blocked receiving
+			// gets an error too, while its sends fail without reaching the
+			// client.
+			return s.err
+		}
+	}
+}
+
+func (in *Injector) faults(method string) (Faults, bool) {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	f, ok := in.cfg.Methods[method]
+	return f, ok
+}
+
+// before injects the faults of f applied before a call is handled.
+func (in *Injector) before(ctx context.Context, f Faults) error {
+	if d := f.Delay; d != nil && in.draw(d.Percent) {
+		t := time.NewTimer(time.Duration(d.Duration))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return status.FromContextError(ctx.Err()).Err()
+		case <-t.C:
+		}
+	}
+	if len(f.Errors) > 0 {
+		// A single draw picks at most one of the errors.
+		p := in.percent()
+		for _, e := range f.Errors {
+			if p < e.Percent {
+				method, _ := grpc.Method(ctx)
+				return newError(e.Code, e.Message, method, "failed")
+			}
+			p -= e.Percent
+		}
+	}
+	return nil
+}
+
+// draw reports whether a fault with the given percentage is injected.
+func (in *Injector) draw(percent float64) bool {
+	return in.percent() < percent
+}
+
+func newError(c codes.Code, msg, method, what string) error {
+	if msg == "" {
+		msg = fmt.Sprintf("fault injected: %v %v", method, what)
+	}
+	return status.Error(c, msg)
+}
+
+// abortingStream fails once the server has sent a number of messages,
+// closing abort.
+type abortingStream struct {
+	grpc.ServerStream
+	err   error
+	abort chan struct{}
+
+	mu   sync.Mutex
+	left int
+}
+
+func (s *abortingStream) SendMsg(m interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.left == 0 {
+		return s.err
+	}
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	if s.left--; s.left == 0 {
+		close(s.abort)
+	}
+	return nil
+}
+
+func (s *abortingStream) aborted() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.left == 0
+}
@@ -0,0 +1,202 @@
+package fault_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-grpc/calculator/calculatorpb"
+	"go-grpc/fault"
+	"go-grpc/fault/faultpb"
+	"go-grpc/greet/greetpb"
+	"go-grpc/greet/greetserver"
+	"go-grpc/harness"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	greet          = "/greet.GreetService/Greet"
+	greetManyTimes = "/greet.GreetService/GreetManyTimes"
+	streamingMax   = "/calculator.CalculatorService/CalculateStreamingMax"
+)
+
+func interceptors(in *fault.Injector) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(in.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(in.StreamInterceptor()),
+	}
+}
+
+func newInjector(t *testing.T, cfg *fault.Config) *fault.Injector {
+	t.Helper()
+	in, err := fault.New("")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := in.Set(cfg); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	return in
+}
+
+func TestLoadConfig(t *testing.T) {
+	for _, path := range []string{
+		"../greet/greet_server/faults.json",
+		"../calculator/calculator_server/faults.json",
+	} {
+		if _, err := fault.LoadConfig(path); err != nil {
+			t.Errorf("LoadConfig(%q) error = %v", path, err)
+		}
+	}
+}
+
+func TestUnary(t *testing.T) {
+	tests := []struct {
+		name   string
+		faults fault.Faults
+		code   codes.Code
+		slow   bool
+	}{
+		{"none", fault.Faults{}, codes.OK, false},
+		{"error", fault.Faults{Errors: []fault.Error{{Code: codes.Unavailable, Percent: 100}}}, codes.Unavailable, false},
+		{"error never", fault.Faults{Errors: []fault.Error{{Code: codes.Unavailable, Percent: 0}}}, codes.OK, false},
+		{"second error", fault.Faults{Errors: []fault.Error{
+			{Code: codes.Unavailable, Percent: 0},
+			{Code: codes.Internal, Percent: 100},
+		}}, codes.Internal, false},
+		{"delay", fault.Faults{Delay: &fault.Delay{Duration: fault.Duration(100 * time.Millisecond), Percent: 100}}, codes.OK, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := newInjector(t, &fault.Config{Methods: map[string]fault.Faults{greet: tt.faults}})
+			c := harness.StartGreet(t, nil, interceptors(in)...)
+			start := time.Now()
+			_, err := c.Greet(context.Background(), &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "Ann"}})
+			if status.Code(err) != tt.code {
+				t.Fatalf("Greet() error = %v, want code %v", err, tt.code)
+			}
+			if slow := time.Since(start) >= 100*time.Millisecond; slow != tt.slow {
+				t.Errorf("Greet() took %v, want slow = %v", time.Since(start), tt.slow)
+			}
+		})
+	}
+}
+
+func TestAbort(t *testing.T) {
+	in := newInjector(t, &fault.Config{Methods: map[string]fault.Faults{
+		greetManyTimes: {Abort: &fault.Abort{AfterMessages: 2, Code: codes.Aborted, Percent: 100}},
+	}})
+	c := harness.StartGreet(t, nil, interceptors(in)...)
+	stream, err := c.GreetManyTimes(context.Background(), &greetpb.GreetManyTimesRequest{
+		Greeting: &greetpb.Greeting{FirstName: "Ann"},
+		Count:    5,
+		Interval: durationpb.New(time.Millisecond),
+	})
+	if err != nil {
+		t.Fatalf("GreetManyTimes() error = %v", err)
+	}
+	var got int
+	for {
+		_, err := stream.Recv()
+		if err == io.EOF {
+			t.Fatalf("GreetManyTimes() ended after %d responses, want code %v", got, codes.Aborted)
+		}
+		if err != nil {
+			if status.Code(err) != codes.Aborted {
+				t.Fatalf("Recv() error = %v, want code %v", err, codes.Aborted)
+			}
+			break
+		}
+		got++
+	}
+	if got != 2 {
+		t.Errorf("GreetManyTimes() sent %d responses before aborting, want 2", got)
+	}
+}
+
+// TestAbortReceiving checks that a stream is aborted while its handler
+// waits for the client.
+func TestAbortReceiving(t *testing.T) {
+	in := newInjector(t, &fault.Config{Methods: map[string]fault.Faults{
+		streamingMax: {Abort: &fault.Abort{AfterMessages: 1, Code: codes.Aborted, Percent: 100}},
+	}})
+	c := harness.StartCalculator(t, nil, interceptors(in)...)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := c.CalculateStreamingMax(ctx)
+	if err != nil {
+		t.Fatalf("CalculateStreamingMax() error = %v", err)
+	}
+	if err := stream.Send(&calculatorpb.CalculatorStreamingRequest{X: 3}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Aborted {
+		t.Errorf("Recv() error = %v, want code %v", err, codes.Aborted)
+	}
+}
+
+func TestAdmin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "faults.json")
+	write := func(s string) {
+		if err := ioutil.WriteFile(path, []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"methods": {"/greet.GreetService/Greet": {"errors": [{"code": "UNAVAILABLE", "percent": 100}]}}}`)
+	in, err := fault.New(path)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	cc := harness.Start(t, func(s *grpc.Server) {
+		greetpb.RegisterGreetServiceServer(s, harness.NewGreetServer(t, greetserver.Options{}))
+		faultpb.RegisterFaultServiceServer(s, fault.NewAdmin(in))
+	}, interceptors(in)...)
+	c := greetpb.NewGreetServiceClient(cc)
+	admin := faultpb.NewFaultServiceClient(cc)
+	ctx := context.Background()
+	greetCode := func() codes.Code {
+		_, err := c.Greet(ctx, &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "Ann"}})
+		return status.Code(err)
+	}
+
+	if got := greetCode(); got != codes.Unavailable {
+		t.Errorf("Greet() code = %v before SetFaults, want %v", got, codes.Unavailable)
+	}
+	if _, err := admin.SetFaults(ctx, &faultpb.SetFaultsRequest{}); err != nil {
+		t.Fatalf("SetFaults() error = %v", err)
+	}
+	if got := greetCode(); got != codes.OK {
+		t.Errorf("Greet() code = %v after SetFaults, want %v", got, codes.OK)
+	}
+
+	write(`{"methods": {"/greet.GreetService/Greet": {"errors": [{"code": 8, "percent": 100}]}}}`)
+	res, err := admin.ReloadFaults(ctx, &faultpb.ReloadFaultsRequest{})
+	if err != nil {
+		t.Fatalf("ReloadFaults() error = %v", err)
+	}
+	if got := greetCode(); got != codes.ResourceExhausted {
+		t.Errorf("Greet() code = %v after ReloadFaults, want %v", got, codes.ResourceExhausted)
+	}
+	if m := res.GetFaults().GetMethods(); len(m) != 1 || m[0].GetMethod() != greet || m[0].GetErrors()[0].GetCode() != uint32(codes.ResourceExhausted) {
+		t.Errorf("ReloadFaults() = %v, want the reloaded faults", res)
+	}
+
+	_, err = admin.SetFaults(ctx, &faultpb.SetFaultsRequest{Faults: &faultpb.Faults{Methods: []*faultpb.MethodFaults{
+		{Method: greet, Errors: []*faultpb.Error{{Code: 14, Percent: 150}}},
+	}}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SetFaults() error = %v, want code %v", err, codes.InvalidArgument)
+	}
+	if got := greetCode(); got != codes.ResourceExhausted {
+		t.Errorf("Greet() code = %v after an invalid SetFaults, want %v", got, codes.ResourceExhausted)
+	}
+}
@@ -1,2 +1,25 @@
-protoc greet/greetpb/greet.proto --go_out=plugins=grpc:.
-protoc calculator/calculatorpb/calculator.proto --go_out=plugins=grpc:.
\ No newline at end of file
+#!/bin/sh
+# Generates the Go code of every .proto file in the buf module at the root of
+# the repository: messages with protoc-gen-go and services with
+# protoc-gen-go-grpc, as configured in buf.gen.yaml. The .proto files are
+# linted and checked for changes breaking existing clients first.
+#
+# Changes are checked against the APIs clients are built against: the
+# baseline tag, or origin/main in clones without it. BUF_AGAINST names
+# another buf input.
+set -e
+cd "$(dirname "$0")"
+buf lint
+against=$BUF_AGAINST
+if [ -z "$against" ]; then
+	if git rev-parse -q --verify refs/tags/baseline >/dev/null; then
+		against=".git#tag=baseline"
+	elif git rev-parse -q --verify refs/remotes/origin/main >/dev/null; then
+		against=".git#branch=origin/main"
+	else
+		echo "generate.sh: no baseline tag or origin/main to check for breaking changes against; fetch the tags or set BUF_AGAINST" >&2
+		exit 1
+	fi
+fi
+buf breaking --against "$against"
+buf generate
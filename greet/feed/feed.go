@@ -0,0 +1,97 @@
+// Package feed fans the greetings produced by the greet server out to live
+// subscribers.
+//
+// Publishing never blocks. Every subscriber has a bounded buffer and
+// greetings arriving while it is full are skipped for that subscriber, which
+// is told how many it missed along with the next greeting it does receive.
+package feed
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"go-grpc/greet/history"
+)
+
+// Delivery is a greeting delivered to a subscriber.
+type Delivery struct {
+	Record history.Record
+	// Missed is the number of greetings skipped right before this one.
+	Missed int64
+}
+
+// Hub delivers every published greeting to the subscribers it matches.
+type Hub struct {
+	buffer int
+
+	// subs is replaced rather than modified, so that Publish only holds mu
+	// while taking it and matches greetings outside the lock.
+	mu   sync.RWMutex
+	subs []*Subscription
+}
+
+// NewHub returns a hub giving every subscriber a buffer of buffer greetings.
+func NewHub(buffer int) *Hub {
+	return &Hub{buffer: buffer}
+}
+
+// Subscription receives the greetings published after it was made.
+type Subscription struct {
+	hub    *Hub
+	match  func(history.Record) bool
+	c      chan Delivery
+	missed int64 // accessed atomically
+}
+
+// Subscribe returns a subscription to the greetings for which match returns
+// true, or to every greeting if match is nil.
+func (h *Hub) Subscribe(match func(history.Record) bool) *Subscription {
+	s := &Subscription{
+		hub:   h,
+		match: match,
+		c:     make(chan Delivery, h.buffer),
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs := make([]*Subscription, len(h.subs), len(h.subs)+1)
+	copy(subs, h.subs)
+	h.subs = append(subs, s)
+	return s
+}
+
+// C returns the channel greetings are delivered on.
+func (s *Subscription) C() <-chan Delivery {
+	return s.c
+}
+
+// Close stops deliveries to the subscription.
+func (s *Subscription) Close() {
+	h := s.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs := make([]*Subscription, 0, len(h.subs))
+	for _, sub := range h.subs {
+		if sub != s {
+			subs = append(subs, sub)
+		}
+	}
+	h.subs = subs
+}
+
+// Publish delivers r to every matching subscriber with room in its buffer.
+func (h *Hub) Publish(r history.Record) {
+	h.mu.RLock()
+	subs := h.subs
+	h.mu.RUnlock()
+	for _, s := range subs {
+		if s.match != nil && !s.match(r) {
+			continue
+		}
+		missed := atomic.SwapInt64(&s.missed, 0)
+		select {
+		case s.c <- Delivery{Record: r, Missed: missed}:
+		default:
+			atomic.AddInt64(&s.missed, missed+1)
+		}
+	}
+}
@@ -2,19 +2,42 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"go-grpc/bidi"
 	"go-grpc/greet/greetpb"
+	"go-grpc/resume"
+	"go-grpc/rpcerror"
+	"go-grpc/serviceconfig"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"io"
 	"log"
 	"time"
 )
 
+var (
+	serviceConfig = flag.String("service_config", "greet/greet_client/service_config.json", "path to the JSON service config with retry and hedging policies, empty to disable")
+	room          = flag.String("room", "", "GreetEveryone room to join, empty to only have greetings echoed back")
+	flowWindow    = flag.Int("flow_window", 0, "maximum number of unacknowledged GreetEveryone responses, 0 to disable flow control")
+)
+
 func main() {
+	flag.Parse()
 	fmt.Println("Hello, I'm a client.")
-	cc, err := grpc.Dial("localhost:50051", grpc.WithInsecure())
+
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+	if *serviceConfig != "" {
+		sc, err := serviceconfig.Load(*serviceConfig)
+		if err != nil {
+			log.Fatalf("Could not load service config: %v", err)
+		}
+		opts = append(opts, sc.DialOptions()...)
+	}
+
+	cc, err := grpc.Dial("localhost:50051", opts...)
 	if err != nil {
 		log.Fatalf("Could not connect: %v", err)
 	}
@@ -28,6 +51,8 @@ func main() {
 	// doServerStreaming(c)
 	// doClientStreaming(c)
 	// doBiDiStreaming(c)
+	// doListGreetings(c, "D")
+	// doSubscribeGreetings(c)
 
 	doUnaryWithDeadline(c, 5*time.Second)
 	doUnaryWithDeadline(c, time.Second)
@@ -43,7 +68,7 @@ func doUnary(c greetpb.GreetServiceClient) {
 	}
 	res, err := c.Greet(context.Background(), req)
 	if err != nil {
-		log.Fatalf("Error while calling Geret RPC: %v", err)
+		log.Fatalf("Error while calling Geret RPC: %v", rpcerror.Describe(err))
 	}
 	log.Printf("Response from Greet: %v", res.Result)
 }
@@ -57,10 +82,22 @@ func doServerStreaming(c greetpb.GreetServiceClient) {
 			LastName:  "Tran",
 		},
 	}
-	resStream, err := c.GreetManyTimes(context.Background(), req)
-	if err != nil {
-		log.Fatalf("Error while calling server streaming: %v", err)
-	}
+	// If the connection drops, the stream is reopened from the last response
+	// received.
+	resStream := resume.NewStream(context.Background(), func(ctx context.Context, token string) (resume.RecvFunc, error) {
+		req.ResumeToken = token
+		stream, err := c.GreetManyTimes(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return func() (resume.Message, error) {
+			msg, err := stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+			return msg, nil
+		}, nil
+	}, resume.Options{})
 
 	for {
 		msg, err := resStream.Recv()
@@ -69,9 +106,10 @@ func doServerStreaming(c greetpb.GreetServiceClient) {
 			break
 		}
 		if err != nil {
-			log.Fatalf("Error while receiving message from streaming server: %v", err)
+			log.Fatalf("Error while receiving message from streaming server: %v", rpcerror.Describe(err))
 		}
-		log.Printf("Response from GreetManyTimes: %v", msg.GetResult())
+		res := msg.(*greetpb.GreetManyTimesResponse)
+		log.Printf("Response %v from GreetManyTimes: %v", res.GetSequence(), res.GetResult())
 	}
 }
 
@@ -118,16 +156,23 @@ func doClientStreaming(c greetpb.GreetServiceClient) {
 	}
 	resp, err := stream.CloseAndRecv()
 	if err != nil {
-		log.Fatalf("Big problem receiving response: %v", err)
+		log.Fatalf("Big problem receiving response: %v", rpcerror.Describe(err))
 	}
-	fmt.Println("LongGreet response: %v", resp.GetResult())
+	fmt.Printf("LongGreet response: %v\n", resp.GetResult())
 }
 
 func doBiDiStreaming(c greetpb.GreetServiceClient) {
 	fmt.Println("Starting to do a BiDi Streaming RPC...")
 
-	// we create a stream by invoking the client
-	stream, err := c.GreetEveryone(context.Background())
+	// we create a stream by invoking the client, in a room if one was asked for
+	ctx := context.Background()
+	if *room != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-greet-room", *room)
+	}
+	if *flowWindow > 0 {
+		ctx = bidi.WithWindow(ctx, *flowWindow)
+	}
+	stream, err := c.GreetEveryone(ctx)
 	if err != nil {
 		log.Fatalf("Error while creating stream: %v", err)
 	}
@@ -155,37 +200,34 @@ func doBiDiStreaming(c greetpb.GreetServiceClient) {
 		},
 	}
 
-	waitc := make(chan struct{})
-	// we send a bunch of messages to the client (go routine)
-	go func() {
-		// function to send a bunch of messages
-		for _, req := range requests {
-			fmt.Println("Sending a message: " + req.GetGreeting().GetFirstName())
-			stream.Send(req)
-			time.Sleep(time.Second)
-		}
-		stream.CloseSend()
-	}()
-
-	// we receive a bunch of messages from the client (go routine)
-	go func() {
-		// function to receive a bunch of messages
-		for {
-			resp, err := stream.Recv()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				log.Fatalf("Error while receiving: %v", err)
-				break
-			}
+	// the session receives on its own goroutine while we send a bunch of
+	// messages, acknowledging every response if flow control is on
+	cfg := bidi.Config{
+		NewResponse: func() interface{} { return new(greetpb.GreetEveryoneResponse) },
+		Handle: func(resp interface{}) error {
 			fmt.Printf("Received: %v\n", resp)
+			return nil
+		},
+	}
+	if *flowWindow > 0 {
+		cfg.Ack = func(resp interface{}) interface{} {
+			return &greetpb.GreetEveryoneRequest{Ack: resp.(*greetpb.GreetEveryoneResponse).GetSequence()}
 		}
-		close(waitc)
-	}()
+	}
+	session := bidi.NewSession(stream, cfg)
+	for _, req := range requests {
+		fmt.Println("Sending a message: " + req.GetGreeting().GetFirstName())
+		if err := session.Send(req); err != nil {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	session.CloseSend()
 
 	// block until everything is done
-	<-waitc
+	if err := session.Wait(); err != nil {
+		log.Fatalf("Error while receiving: %v", rpcerror.Describe(err))
+	}
 }
 
 func doUnaryWithDeadline(c greetpb.GreetServiceClient, timeout time.Duration) {
@@ -202,10 +244,13 @@ func doUnaryWithDeadline(c greetpb.GreetServiceClient, timeout time.Duration) {
 	if err != nil {
 		stsErr, ok := status.FromError(err)
 		if ok {
-			if stsErr.Code() == codes.DeadlineExceeded {
+			switch stsErr.Code() {
+			case codes.DeadlineExceeded:
 				fmt.Println("Timeout was hit! Deadline was exceeded.")
-			} else {
-				fmt.Println("Unexpected error: %v", stsErr)
+			case codes.Canceled:
+				fmt.Println("The request was cancelled.")
+			default:
+				fmt.Printf("Unexpected error: %v\n", rpcerror.Describe(stsErr.Err()))
 			}
 		} else {
 			log.Fatalf("Error while calling Geret RPC: %v", err)
@@ -214,3 +259,56 @@ func doUnaryWithDeadline(c greetpb.GreetServiceClient, timeout time.Duration) {
 	}
 	log.Printf("Response from Greet: %v", res.Result)
 }
+
+func doListGreetings(c greetpb.GreetServiceClient, namePrefix string) {
+	fmt.Println("Starting to list the greeting history...")
+	req := &greetpb.ListGreetingsRequest{
+		NamePrefix: namePrefix,
+		PageSize:   10,
+	}
+	for {
+		stream, err := c.ListGreetings(context.Background(), req)
+		if err != nil {
+			log.Fatalf("Error while calling ListGreetings: %v", err)
+		}
+		next := ""
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Fatalf("Error while listing greetings: %v", rpcerror.Describe(err))
+			}
+			g := res.GetGreeting()
+			fmt.Printf("%v %v greeted %v %v from %v: %v\n", g.GetTime().AsTime().Format(time.RFC3339), g.GetRpc(), g.GetFirstName(), g.GetLastName(), g.GetPeer(), g.GetResult())
+			next = res.GetNextPageToken()
+		}
+		if next == "" {
+			return
+		}
+		req.PageToken = next
+	}
+}
+
+func doSubscribeGreetings(c greetpb.GreetServiceClient, prefixes ...string) {
+	fmt.Println("Starting to follow the greetings of every client...")
+	stream, err := c.SubscribeGreetings(context.Background(), &greetpb.SubscribeGreetingsRequest{
+		NamePrefixes: prefixes,
+	})
+	if err != nil {
+		log.Fatalf("Error while calling SubscribeGreetings RPC: %v", err)
+	}
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			fmt.Printf("Stopped following greetings: %v\n", rpcerror.Describe(err))
+			return
+		}
+		if res.GetMissed() > 0 {
+			fmt.Printf("Missed %v greetings\n", res.GetMissed())
+		}
+		g := res.GetGreeting()
+		fmt.Printf("%v %v greeted %v: %v\n", g.GetTime().AsTime().Format(time.RFC3339), g.GetRpc(), g.GetPeer(), g.GetResult())
+	}
+}
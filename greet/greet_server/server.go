@@ -1,118 +1,127 @@
 package main
 
 import (
-	"context"
+	"flag"
 	"fmt"
+	"go-grpc/deadline"
+	"go-grpc/fault"
+	"go-grpc/fault/faultpb"
+	"go-grpc/greet/greeting"
 	"go-grpc/greet/greetpb"
+	"go-grpc/greet/greetserver"
+	"go-grpc/greet/history"
+	"go-grpc/ratelimit"
+	"go-grpc/replay"
+	"go-grpc/validate"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-	"io"
 	"log"
 	"net"
-	"strconv"
-	"time"
 )
 
-type server struct {
-}
+var (
+	locales        = flag.String("locales", "greet/greet_server/locales", "directory of the greeting template catalog, one JSON file per locale")
+	historyStore   = flag.String("history_store", history.Bolt, "embedded store for the greeting history: bolt or sqlite")
+	historyPath    = flag.String("history", "", "path of the greeting history database, empty to disable history")
+	rateLimits     = flag.String("rate_limits", "greet/greet_server/rate_limits.json", "path to the JSON per-method rate limit config, empty to disable")
+	deadlines      = flag.String("deadlines", "greet/greet_server/deadlines.json", "path to the JSON per-method default and maximum deadline config, empty to disable")
+	faultInjection = flag.Bool("fault_injection", false, "inject the faults of -faults into calls and serve the FaultService RPCs changing them, for testing clients")
+	faults         = flag.String("faults", "greet/greet_server/faults.json", "path to the JSON per-method fault config used by -fault_injection, empty to start without faults")
+	record         = flag.String("record", "", "path of a file to record every call to, for replaying against another server with replay_client, empty to disable")
+	feedBuffer     = flag.Int("feed_buffer", 256, "number of greetings buffered per SubscribeGreetings caller before it starts missing some")
+	roomBuffer     = flag.Int("room_buffer", 64, "number of GreetEveryone room events buffered per participant before it is disconnected for falling behind")
+	maxStreamCount = flag.Int("max_stream_count", 1000, "maximum number of responses a GreetManyTimes call may ask for")
+)
 
-func (*server) Greet(_ context.Context, req *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
-	fmt.Printf("Greet function was invoked with %v", req)
-	firstName := req.GetGreeting().GetFirstName()
-	result := "Hello, " + firstName
-	res := &greetpb.GreetResponse{
-		Result: result,
-	}
-	return res, nil
-}
+func main() {
+	flag.Parse()
+	fmt.Println("Hello world!")
 
-func (*server) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreetService_GreetManyTimesServer) error {
-	firstName := req.GetGreeting().GetFirstName()
-	for i := 0; i < 10; i++ {
-		result := "Hello " + firstName + " number " + strconv.Itoa(i)
-		res := &greetpb.GreetManyTimesResponse{
-			Result: result,
-		}
-		stream.Send(res)
-		time.Sleep(time.Second)
+	lis, err := net.Listen("tcp", "0.0.0.0:50051")
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	return nil
-}
-
-func (*server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
-	fmt.Printf("LongGreet function was invoked with stream request")
-	result := "Hello "
+	greetings, err := greeting.LoadCatalog(*locales)
+	if err != nil {
+		log.Fatalf("Failed to load greeting catalog: %v", err)
+	}
 
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			// We have finished reading the client stream
-			return stream.SendAndClose(&greetpb.LongGreetResponse{
-				Result: result,
-			})
-		}
+	var store history.Store
+	if *historyPath != "" {
+		store, err = history.Open(*historyStore, *historyPath)
 		if err != nil {
-			log.Fatalf("Error while reading client stream %v", err)
+			log.Fatalf("Failed to open greeting history: %v", err)
 		}
-
-		firstName := req.GetGreeting().GetFirstName()
-		result += firstName + "! "
+		defer store.Close()
 	}
-}
+	srv := greetserver.New(greetserver.Options{
+		Greetings:      greetings,
+		History:        store,
+		MaxStreamCount: *maxStreamCount,
+		RoomBuffer:     *roomBuffer,
+		FeedBuffer:     *feedBuffer,
+	})
 
-func (*server) GreetEveryone(stream greetpb.GreetService_GreetEveryoneServer) error {
-	fmt.Printf("GreetEveryone function was invoked.\n")
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			return nil
-		}
+	var opts []grpc.ServerOption
+	if *record != "" {
+		rec, err := replay.Open(*record)
 		if err != nil {
-			log.Fatalf("Error while reading client stream: %v", err)
-			return err
+			log.Fatalf("Failed to open recording: %v", err)
 		}
-		firstName := req.GetGreeting().GetFirstName()
-		result := "Hello " + firstName + "! "
-		err = stream.Send(&greetpb.GreetEveryoneResponse{
-			Result: result,
-		})
+		defer rec.Close()
+		r := replay.NewRecorder(rec)
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(r.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(r.StreamInterceptor()),
+		)
+	}
+	if *rateLimits != "" {
+		cfg, err := ratelimit.LoadConfig(*rateLimits)
 		if err != nil {
-			log.Fatalf("Error while sending data to client: %v", err)
-			return err
+			log.Fatalf("Failed to load rate limits: %v", err)
 		}
+		l := ratelimit.New(cfg)
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(l.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(l.StreamInterceptor()),
+		)
 	}
-}
-
-func (*server) GreetWithDeadline(ctx context.Context, req *greetpb.GreetWithDeadlineRequest) (*greetpb.GreetWithDeadlineResponse, error) {
-	fmt.Printf("GreetWithDeadline function was invoked with %v\n", req)
-	for i := 0; i < 3; i++ {
-		if ctx.Err() == context.Canceled {
-			fmt.Println("The client canceled the request!")
-			return nil, status.Error(codes.DeadlineExceeded, "The client cancelled the request")
+	if *deadlines != "" {
+		cfg, err := deadline.LoadConfig(*deadlines)
+		if err != nil {
+			log.Fatalf("Failed to load deadlines: %v", err)
 		}
-		time.Sleep(time.Second)
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(deadline.UnaryInterceptor(cfg)),
+			grpc.ChainStreamInterceptor(deadline.StreamInterceptor(cfg)),
+		)
 	}
-	firstName := req.GetGreeting().GetFirstName()
-	result := "Hello, " + firstName
-	res := &greetpb.GreetWithDeadlineResponse{
-		Result: result,
+	opts = append(opts,
+		// The codec lets names that are not valid UTF-8 reach the
+		// validation, which rejects them as InvalidArgument.
+		grpc.ForceServerCodec(validate.Codec{}),
+		grpc.ChainUnaryInterceptor(validate.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(validate.StreamServerInterceptor()),
+	)
+	var injector *fault.Injector
+	if *faultInjection {
+		injector, err = fault.New(*faults)
+		if err != nil {
+			log.Fatalf("Failed to load faults: %v", err)
+		}
+		log.Printf("Fault injection enabled")
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(injector.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(injector.StreamInterceptor()),
+		)
 	}
-	return res, nil
-}
-
-func main() {
-	fmt.Println("Hello world!")
 
-	lis, err := net.Listen("tcp", "0.0.0.0:50051")
-	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
+	s := grpc.NewServer(opts...)
+	greetpb.RegisterGreetServiceServer(s, srv)
+	if injector != nil {
+		faultpb.RegisterFaultServiceServer(s, fault.NewAdmin(injector))
 	}
 
-	s := grpc.NewServer()
-	greetpb.RegisterGreetServiceServer(s, &server{})
-
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
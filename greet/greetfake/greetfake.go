@@ -0,0 +1,102 @@
+// Package greetfake provides a scriptable fake of the greet service, for
+// testing its clients.
+//
+// Every method fails with Unimplemented until it is scripted:
+//
+//	s := greetfake.New()
+//	s.On("Greet").Return(&greetpb.GreetResponse{Result: "Hello Ann"}).
+//		FailCall(3, status.Error(codes.Unavailable, "down"))
+//	c := harness.StartGreet(t, s)
+package greetfake
+
+import (
+	"context"
+	"fmt"
+
+	"go-grpc/fake"
+	"go-grpc/greet/greetpb"
+	"google.golang.org/protobuf/proto"
+)
+
+// Server is a fake greet server.
+type Server struct {
+	greetpb.UnimplementedGreetServiceServer
+
+	methods map[string]*fake.Method
+}
+
+var _ greetpb.GreetServiceServer = (*Server)(nil)
+
+// New returns a fake greet server whose methods are all unscripted.
+func New() *Server {
+	s := &Server{methods: make(map[string]*fake.Method)}
+	for _, name := range []string{
+		"Greet",
+		"GreetManyTimes",
+		"LongGreet",
+		"GreetEveryone",
+		"GreetWithDeadline",
+		"ListGreetings",
+		"SubscribeGreetings",
+	} {
+		s.methods[name] = fake.NewMethod(name)
+	}
+	return s
+}
+
+// On returns the script of the method called name, such as "Greet". It
+// panics if the service has no such method.
+func (s *Server) On(name string) *fake.Method {
+	m, ok := s.methods[name]
+	if !ok {
+		panic(fmt.Sprintf("greetfake: GreetService has no method %q", name))
+	}
+	return m
+}
+
+// Reset forgets the scripts and the calls of every method.
+func (s *Server) Reset() {
+	for _, m := range s.methods {
+		m.Reset()
+	}
+}
+
+func (s *Server) Greet(ctx context.Context, req *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
+	res, err := s.On("Greet").Unary(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*greetpb.GreetResponse), nil
+}
+
+func (s *Server) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreetService_GreetManyTimesServer) error {
+	return s.On("GreetManyTimes").ServerStream(req, stream)
+}
+
+func (s *Server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
+	return s.On("LongGreet").ClientStream(stream, func() proto.Message {
+		return new(greetpb.LongGreetRequest)
+	})
+}
+
+func (s *Server) GreetEveryone(stream greetpb.GreetService_GreetEveryoneServer) error {
+	return s.On("GreetEveryone").BidiStream(stream, func() proto.Message {
+		return new(greetpb.GreetEveryoneRequest)
+	})
+}
+
+func (s *Server) GreetWithDeadline(ctx context.Context, req *greetpb.GreetWithDeadlineRequest) (*greetpb.GreetWithDeadlineResponse, error) {
+	res, err := s.On("GreetWithDeadline").Unary(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*greetpb.GreetWithDeadlineResponse), nil
+}
+
+func (s *Server) ListGreetings(req *greetpb.ListGreetingsRequest, stream greetpb.GreetService_ListGreetingsServer) error {
+	return s.On("ListGreetings").ServerStream(req, stream)
+}
+
+func (s *Server) SubscribeGreetings(req *greetpb.SubscribeGreetingsRequest, stream greetpb.GreetService_SubscribeGreetingsServer) error {
+	return s.On("SubscribeGreetings").ServerStream(req, stream)
+}
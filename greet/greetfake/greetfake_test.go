@@ -0,0 +1,129 @@
+package greetfake_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"go-grpc/greet/greetfake"
+	"go-grpc/greet/greetpb"
+	"go-grpc/harness"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGreet(t *testing.T) {
+	s := greetfake.New()
+	s.On("Greet").Return(
+		&greetpb.GreetResponse{Result: "Hello Ann"},
+		&greetpb.GreetResponse{Result: "Hello again"},
+	).FailCall(3, status.Error(codes.Unavailable, "down"))
+	c := harness.StartGreet(t, s)
+
+	tests := []struct {
+		want string
+		code codes.Code
+	}{
+		{"Hello Ann", codes.OK},
+		{"Hello again", codes.OK},
+		{"", codes.Unavailable},
+		{"Hello again", codes.OK},
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-client-id", "test")
+	for i, tt := range tests {
+		res, err := c.Greet(ctx, &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "Ann"}})
+		if status.Code(err) != tt.code {
+			t.Fatalf("call %d: Greet() error = %v, want code %v", i+1, err, tt.code)
+		}
+		if got := res.GetResult(); got != tt.want {
+			t.Errorf("call %d: Greet() = %q, want %q", i+1, got, tt.want)
+		}
+	}
+
+	calls := s.On("Greet").Calls()
+	if len(calls) != len(tests) {
+		t.Fatalf("Calls() = %d calls, want %d", len(calls), len(tests))
+	}
+	want := &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "Ann"}}
+	if got := calls[0].Requests[0]; !proto.Equal(got, want) {
+		t.Errorf("Calls()[0].Requests[0] = %v, want %v", got, want)
+	}
+	if got := calls[0].Metadata.Get("x-client-id"); len(got) != 1 || got[0] != "test" {
+		t.Errorf("Calls()[0].Metadata x-client-id = %q, want [test]", got)
+	}
+	if got := status.Code(calls[2].Err); got != codes.Unavailable {
+		t.Errorf("Calls()[2].Err code = %v, want %v", got, codes.Unavailable)
+	}
+}
+
+func TestUnscripted(t *testing.T) {
+	c := harness.StartGreet(t, greetfake.New())
+	_, err := c.Greet(context.Background(), &greetpb.GreetRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("Greet() error = %v, want code %v", err, codes.Unimplemented)
+	}
+}
+
+func TestGreetManyTimes(t *testing.T) {
+	s := greetfake.New()
+	s.On("GreetManyTimes").Return(
+		&greetpb.GreetManyTimesResponse{Result: "one"},
+		&greetpb.GreetManyTimesResponse{Result: "two"},
+	).Delay(20 * time.Millisecond).Fail(status.Error(codes.Aborted, "cut"))
+	c := harness.StartGreet(t, s)
+
+	start := time.Now()
+	stream, err := c.GreetManyTimes(context.Background(), &greetpb.GreetManyTimesRequest{Count: 2})
+	if err != nil {
+		t.Fatalf("GreetManyTimes() error = %v", err)
+	}
+	var got []string
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			t.Fatal("Recv() ended the stream, want code Aborted")
+		}
+		if err != nil {
+			if status.Code(err) != codes.Aborted {
+				t.Fatalf("Recv() error = %v, want code %v", err, codes.Aborted)
+			}
+			break
+		}
+		got = append(got, res.GetResult())
+	}
+	if len(got) != 2 || got[0] != "one" || got[1] != "two" {
+		t.Errorf("GreetManyTimes() = %q, want [one two]", got)
+	}
+	if d := time.Since(start); d < 40*time.Millisecond {
+		t.Errorf("GreetManyTimes() took %v, want at least 40ms", d)
+	}
+}
+
+func TestLongGreet(t *testing.T) {
+	s := greetfake.New()
+	s.On("LongGreet").Return(&greetpb.LongGreetResponse{Result: "Hello all"})
+	c := harness.StartGreet(t, s)
+
+	stream, err := c.LongGreet(context.Background())
+	if err != nil {
+		t.Fatalf("LongGreet() error = %v", err)
+	}
+	for _, name := range []string{"Ann", "Bob"} {
+		if err := stream.Send(&greetpb.LongGreetRequest{Greeting: &greetpb.Greeting{FirstName: name}}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("CloseAndRecv() error = %v", err)
+	}
+	if res.GetResult() != "Hello all" {
+		t.Errorf("LongGreet() = %q, want %q", res.GetResult(), "Hello all")
+	}
+	if calls := s.On("LongGreet").Calls(); len(calls) != 1 || len(calls[0].Requests) != 2 {
+		t.Errorf("Calls() = %v, want 1 call of 2 requests", calls)
+	}
+}
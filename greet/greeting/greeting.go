@@ -0,0 +1,227 @@
+// Package greeting renders localised greetings from a catalog of templates.
+//
+// A catalog is a directory holding one JSON file per locale, named after its
+// BCP 47 tag, e.g. "en.json", "fr.json" or "fr-CA.json":
+//
+//	{
+//	  "timeOfDay": {"morning": "Good morning", "afternoon": "Good afternoon", ...},
+//	  "honorifics": {"dr": "Dr.", ...},
+//	  "templates": {"greeting": "{{.TimeOfDay}}, {{.Name}}", ...}
+//	}
+//
+// Templates are text/template templates executed with a Data value. Lookups
+// fall back along the locale's parents to English, key by key, so "fr-CA"
+// only has to define what differs from "fr".
+package greeting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// BaseLocale ends every fallback chain and must be present in a catalog.
+const BaseLocale = "en"
+
+// Template keys used by the greet server.
+const (
+	// Single is a greeting for one person.
+	Single = "greeting"
+	// Numbered is one greeting of a numbered series, with Data.Number set.
+	Numbered = "numbered"
+	// Group is a greeting for several people, with Data.Names set.
+	Group = "group"
+)
+
+// Person is who is being greeted.
+type Person struct {
+	FirstName string
+	LastName  string
+	// Honorific is a key of the catalog's honorifics, such as "dr". Unknown
+	// honorifics are used verbatim.
+	Honorific string
+}
+
+// Data is what templates are executed with.
+type Data struct {
+	FirstName string
+	LastName  string
+	// Honorific is the localised honorific, if any.
+	Honorific string
+	// Name is the full name with honorific, e.g. "Dr. Ada Lovelace".
+	Name string
+	// TimeOfDay is the localised salutation for the current time of day,
+	// e.g. "Good morning".
+	TimeOfDay string
+	// Number is the position of the greeting in a numbered series.
+	Number int
+	// Names are the full names of everyone greeted by a group greeting.
+	Names []string
+}
+
+type locale struct {
+	TimeOfDay  map[string]string `json:"timeOfDay"`
+	Honorifics map[string]string `json:"honorifics"`
+	Templates  map[string]string `json:"templates"`
+
+	templates map[string]*template.Template
+}
+
+// Catalog holds the templates of every locale. It is safe for concurrent use
+// once loaded.
+type Catalog struct {
+	locales map[string]*locale
+	// Now returns the time used to pick the time of day. It defaults to
+	// time.Now.
+	Now func() time.Time
+}
+
+var funcs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// LoadCatalog reads every *.json file in dir.
+func LoadCatalog(dir string) (*Catalog, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{
+		locales: make(map[string]*locale),
+		Now:     time.Now,
+	}
+	for _, p := range paths {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		l := &locale{templates: make(map[string]*template.Template)}
+		if err := json.Unmarshal(b, l); err != nil {
+			return nil, fmt.Errorf("parsing %v: %v", p, err)
+		}
+		for k, text := range l.Templates {
+			t, err := template.New(k).Funcs(funcs).Option("missingkey=error").Parse(text)
+			if err != nil {
+				return nil, fmt.Errorf("parsing template %q of %v: %v", k, p, err)
+			}
+			l.templates[k] = t
+		}
+		c.locales[normalize(strings.TrimSuffix(filepath.Base(p), ".json"))] = l
+	}
+	if _, ok := c.locales[BaseLocale]; !ok {
+		return nil, fmt.Errorf("catalog %v has no %q locale", dir, BaseLocale)
+	}
+	return c, nil
+}
+
+// Render executes the template key of the given locale for people. Group
+// greetings take any number of people, all other keys take the first one.
+// number is only used by Numbered.
+func (c *Catalog) Render(tag, key string, number int, people ...Person) (string, error) {
+	chain := c.chain(tag)
+	var t *template.Template
+	for _, l := range chain {
+		if t = l.templates[key]; t != nil {
+			break
+		}
+	}
+	if t == nil {
+		return "", fmt.Errorf("no template %q for locale %q", key, tag)
+	}
+
+	d := Data{
+		Number:    number,
+		TimeOfDay: lookup(chain, func(l *locale) map[string]string { return l.TimeOfDay }, period(c.Now().Hour())),
+	}
+	for i, p := range people {
+		name := c.name(chain, p)
+		d.Names = append(d.Names, name)
+		if i == 0 {
+			d.FirstName = p.FirstName
+			d.LastName = p.LastName
+			d.Honorific = c.honorific(chain, p.Honorific)
+			d.Name = name
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, d); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// chain returns the locales to try for tag, most specific first.
+func (c *Catalog) chain(tag string) []*locale {
+	var chain []*locale
+	for tag = normalize(tag); tag != "" && tag != BaseLocale; tag = parent(tag) {
+		if l, ok := c.locales[tag]; ok {
+			chain = append(chain, l)
+		}
+	}
+	return append(chain, c.locales[BaseLocale])
+}
+
+func (c *Catalog) honorific(chain []*locale, h string) string {
+	if h == "" {
+		return ""
+	}
+	if s := lookup(chain, func(l *locale) map[string]string { return l.Honorifics }, strings.ToLower(h)); s != "" {
+		return s
+	}
+	return h
+}
+
+func (c *Catalog) name(chain []*locale, p Person) string {
+	var parts []string
+	for _, s := range []string{c.honorific(chain, p.Honorific), p.FirstName, p.LastName} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// lookup returns the first value of key found in the maps returned by table
+// along chain.
+func lookup(chain []*locale, table func(*locale) map[string]string, key string) string {
+	for _, l := range chain {
+		if v, ok := table(l)[key]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// period names the part of the day an hour falls into.
+func period(hour int) string {
+	switch {
+	case hour >= 5 && hour < 12:
+		return "morning"
+	case hour >= 12 && hour < 17:
+		return "afternoon"
+	case hour >= 17 && hour < 22:
+		return "evening"
+	default:
+		return "night"
+	}
+}
+
+// normalize lower-cases a locale tag and uses "-" as its separator.
+func normalize(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+// parent drops the last subtag of tag, e.g. "fr-ca" becomes "fr" and "fr"
+// becomes "".
+func parent(tag string) string {
+	if i := strings.LastIndex(tag, "-"); i >= 0 {
+		return tag[:i]
+	}
+	return ""
+}
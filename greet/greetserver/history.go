@@ -0,0 +1,134 @@
+package greetserver
+
+import (
+	"context"
+	"go-grpc/greet/greetpb"
+	"go-grpc/greet/history"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	// maxPageSize bounds the page size ListGreetings accepts.
+	maxPageSize = 1000
+	// maxNamePrefixes bounds the number of prefixes a SubscribeGreetings
+	// call may match, which every greeting is matched against.
+	maxNamePrefixes = 100
+)
+
+// record publishes a greeting to the subscribers of the feed and persists it
+// if history is enabled. Failing to persist is logged rather than failing the
+// RPC that produced the greeting.
+func (s *Server) record(ctx context.Context, rpc string, g *greetpb.Greeting, result string) {
+	r := history.Record{
+		FirstName: g.GetFirstName(),
+		LastName:  g.GetLastName(),
+		Time:      time.Now(),
+		RPC:       rpc,
+		Result:    result,
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		r.Peer = p.Addr.String()
+	}
+	if s.feed != nil {
+		s.feed.Publish(r)
+	}
+	if s.history == nil {
+		return
+	}
+	if err := s.history.Add(ctx, r); err != nil {
+		log.Printf("Failed to record greeting: %v", err)
+	}
+}
+
+func (s *Server) ListGreetings(req *greetpb.ListGreetingsRequest, stream greetpb.GreetService_ListGreetingsServer) error {
+	if s.history == nil {
+		return status.Error(codes.FailedPrecondition, "Greeting history is disabled on this server")
+	}
+	if req.GetPageSize() < 0 || req.GetPageSize() > maxPageSize {
+		return status.Errorf(codes.InvalidArgument, "Page size must be between 1 and %v, got %v", maxPageSize, req.GetPageSize())
+	}
+	q := history.Query{
+		NamePrefix: req.GetNamePrefix(),
+		PageSize:   int(req.GetPageSize()),
+		PageToken:  req.GetPageToken(),
+	}
+	if t := req.GetStartTime(); t != nil {
+		q.Start = t.AsTime()
+	}
+	if t := req.GetEndTime(); t != nil {
+		q.End = t.AsTime()
+	}
+
+	records, next, err := s.history.List(stream.Context(), q)
+	if err == history.ErrInvalidPageToken {
+		return status.Error(codes.InvalidArgument, "Invalid page token")
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "Could not list greetings: %v", err)
+	}
+	for i, r := range records {
+		res := &greetpb.ListGreetingsResponse{
+			Greeting: greetingRecord(r),
+		}
+		if i == len(records)-1 {
+			res.NextPageToken = next
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) SubscribeGreetings(req *greetpb.SubscribeGreetingsRequest, stream greetpb.GreetService_SubscribeGreetingsServer) error {
+	ctx := stream.Context()
+	prefixes := req.GetNamePrefixes()
+	if len(prefixes) > maxNamePrefixes {
+		return status.Errorf(codes.InvalidArgument, "At most %v name prefixes may be given, got %v", maxNamePrefixes, len(prefixes))
+	}
+	var match func(history.Record) bool
+	if len(prefixes) > 0 {
+		match = func(r history.Record) bool {
+			for _, p := range prefixes {
+				if strings.HasPrefix(r.FirstName, p) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	sub := s.feed.Subscribe(match)
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case d := <-sub.C():
+			err := stream.Send(&greetpb.SubscribeGreetingsResponse{
+				Greeting: greetingRecord(d.Record),
+				Missed:   d.Missed,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func greetingRecord(r history.Record) *greetpb.GreetingRecord {
+	return &greetpb.GreetingRecord{
+		FirstName: r.FirstName,
+		LastName:  r.LastName,
+		Time:      timestamppb.New(r.Time),
+		Rpc:       r.RPC,
+		Peer:      r.Peer,
+		Result:    r.Result,
+	}
+}
@@ -0,0 +1,434 @@
+// Package greetserver implements greetpb.GreetService.
+package greetserver
+
+import (
+	"context"
+	"fmt"
+	"go-grpc/bidi"
+	"go-grpc/greet/feed"
+	"go-grpc/greet/greeting"
+	"go-grpc/greet/greetpb"
+	"go-grpc/greet/history"
+	"go-grpc/greet/room"
+	"go-grpc/resume"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"io"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Options configure a Server. Zero values select the defaults.
+type Options struct {
+	// Greetings renders the greetings. It is required.
+	Greetings *greeting.Catalog
+	// History persists greetings, nil to disable it.
+	History history.Store
+	// MaxStreamCount is the maximum number of responses a GreetManyTimes
+	// call may ask for, 1000 by default.
+	MaxStreamCount int
+	// RoomBuffer is the number of GreetEveryone room events buffered per
+	// participant before it is disconnected for falling behind, 64 by
+	// default.
+	RoomBuffer int
+	// FeedBuffer is the number of greetings buffered per SubscribeGreetings
+	// caller before it starts missing some, 256 by default.
+	FeedBuffer int
+}
+
+// Server implements greetpb.GreetServiceServer.
+type Server struct {
+	greetpb.UnimplementedGreetServiceServer
+
+	greetings *greeting.Catalog
+	// history persists greetings, nil if disabled.
+	history history.Store
+	// rooms holds the GreetEveryone chat rooms.
+	rooms *room.Hub
+	// feed streams greetings to SubscribeGreetings callers.
+	feed *feed.Hub
+	// maxStreamCount bounds the count of GreetManyTimes requests.
+	maxStreamCount int
+}
+
+// New returns a server configured by opts.
+func New(opts Options) *Server {
+	if opts.MaxStreamCount == 0 {
+		opts.MaxStreamCount = 1000
+	}
+	if opts.RoomBuffer == 0 {
+		opts.RoomBuffer = 64
+	}
+	if opts.FeedBuffer == 0 {
+		opts.FeedBuffer = 256
+	}
+	return &Server{
+		greetings:      opts.Greetings,
+		history:        opts.History,
+		rooms:          room.NewHub(opts.RoomBuffer),
+		feed:           feed.NewHub(opts.FeedBuffer),
+		maxStreamCount: opts.MaxStreamCount,
+	}
+}
+
+// render localises the greeting template key for the people in greetings,
+// using the locale of the first one.
+func (s *Server) render(key string, number int, greetings ...*greetpb.Greeting) (string, error) {
+	people := make([]greeting.Person, len(greetings))
+	for i, g := range greetings {
+		people[i] = greeting.Person{
+			FirstName: g.GetFirstName(),
+			LastName:  g.GetLastName(),
+			Honorific: g.GetHonorific(),
+		}
+	}
+	var locale string
+	if len(greetings) > 0 {
+		locale = greetings[0].GetLocale()
+	}
+	result, err := s.greetings.Render(locale, key, number, people...)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "Could not render greeting: %v", err)
+	}
+	return result, nil
+}
+
+func (s *Server) Greet(ctx context.Context, req *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
+	fmt.Printf("Greet function was invoked with %v", req)
+	result, err := s.render(greeting.Single, 0, req.GetGreeting())
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, "Greet", req.GetGreeting(), result)
+	res := &greetpb.GreetResponse{
+		Result: result,
+	}
+	return res, nil
+}
+
+const (
+	defaultStreamCount    = 10
+	defaultStreamInterval = time.Second
+)
+
+func (s *Server) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreetService_GreetManyTimesServer) error {
+	count := int(req.GetCount())
+	if count == 0 {
+		count = defaultStreamCount
+	}
+	if count < 0 || count > s.maxStreamCount {
+		return status.Errorf(codes.InvalidArgument, "Count must be between 1 and %v, got %v", s.maxStreamCount, count)
+	}
+	interval, err := durationOrDefault(req.GetInterval(), defaultStreamInterval)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid interval: %v", err)
+	}
+	jitter, err := durationOrDefault(req.GetJitter(), 0)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid jitter: %v", err)
+	}
+
+	// A resumed stream starts right after the last response the client got.
+	sent, err := resume.Position(req)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Cannot resume GreetManyTimes: %v", err)
+	}
+
+	for i := int(sent); i < count; i++ {
+		result, err := s.render(greeting.Numbered, i, req.GetGreeting())
+		if err != nil {
+			return err
+		}
+		seq := uint64(i + 1)
+		res := &greetpb.GreetManyTimesResponse{
+			Result:      result,
+			Sequence:    seq,
+			ResumeToken: resume.Token(req, seq),
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+		s.record(stream.Context(), "GreetManyTimes", req.GetGreeting(), result)
+		if i == count-1 {
+			break
+		}
+
+		wait := interval
+		if jitter > 0 {
+			wait += time.Duration(rand.Int63n(int64(jitter)))
+		}
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-time.After(wait):
+		}
+	}
+
+	return nil
+}
+
+// durationOrDefault converts d, falling back to def if it is unset.
+func durationOrDefault(d *durationpb.Duration, def time.Duration) (time.Duration, error) {
+	if d == nil {
+		return def, nil
+	}
+	if err := d.CheckValid(); err != nil {
+		return 0, err
+	}
+	if d.AsDuration() < 0 {
+		return 0, fmt.Errorf("negative duration %v", d.AsDuration())
+	}
+	return d.AsDuration(), nil
+}
+
+func (s *Server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
+	fmt.Printf("LongGreet function was invoked with stream request")
+	var greetings []*greetpb.Greeting
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			// We have finished reading the client stream
+			result, err := s.render(greeting.Group, 0, greetings...)
+			if err != nil {
+				return err
+			}
+			for _, g := range greetings {
+				s.record(stream.Context(), "LongGreet", g, result)
+			}
+			return stream.SendAndClose(&greetpb.LongGreetResponse{
+				Result: result,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		greetings = append(greetings, req.GetGreeting())
+	}
+}
+
+// roomKey is the metadata key naming the GreetEveryone room to join.
+const roomKey = "x-greet-room"
+
+// everyone is a GreetEveryone stream, whose requests are received on their
+// own goroutine so that the handler can keep sending meanwhile.
+type everyone struct {
+	greetpb.GreetService_GreetEveryoneServer
+	window  *bidi.Window
+	reqs    <-chan interface{}
+	recvErr <-chan error
+}
+
+// greeting returns the greeting of req, or nil if req only acknowledges
+// responses.
+func (st *everyone) greeting(req *greetpb.GreetEveryoneRequest) (*greetpb.Greeting, error) {
+	if req.GetAck() != 0 {
+		return nil, st.window.Ack(req.GetAck())
+	}
+	if req.GetGreeting() == nil {
+		return nil, status.Error(codes.InvalidArgument, "A greeting is required unless the message acknowledges responses")
+	}
+	return req.GetGreeting(), nil
+}
+
+// stopReceiving is called once the client closed its side of the stream.
+// Since it can no longer acknowledge anything, the window stops applying.
+func (st *everyone) stopReceiving() {
+	st.window.Close()
+	st.reqs, st.recvErr = nil, nil
+}
+
+func (s *Server) GreetEveryone(stream greetpb.GreetService_GreetEveryoneServer) error {
+	fmt.Printf("GreetEveryone function was invoked.\n")
+	w, err := bidi.WindowFromContext(stream.Context())
+	if err != nil {
+		return err
+	}
+	reqs, recvErr := bidi.Receive(stream, func() interface{} {
+		return new(greetpb.GreetEveryoneRequest)
+	})
+	st := &everyone{GreetService_GreetEveryoneServer: stream, window: w, reqs: reqs, recvErr: recvErr}
+
+	var first *greetpb.GreetEveryoneRequest
+	select {
+	case req := <-reqs:
+		first = req.(*greetpb.GreetEveryoneRequest)
+	case err := <-recvErr:
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+	if _, err := st.greeting(first); err != nil {
+		return err
+	}
+
+	name := first.GetRoom()
+	if md, ok := metadata.FromIncomingContext(stream.Context()); ok {
+		if rooms := md.Get(roomKey); len(rooms) > 0 && rooms[0] != "" {
+			name = rooms[0]
+		}
+	}
+	if name == "" {
+		return s.echoEveryone(st, first)
+	}
+	return s.chat(st, name, first)
+}
+
+// echoEveryone greets every person back to the client that sent them,
+// queueing the greetings while the window of the client is full.
+func (s *Server) echoEveryone(st *everyone, req *greetpb.GreetEveryoneRequest) error {
+	var pending []*greetpb.GreetEveryoneResponse
+	for {
+		if req != nil {
+			g, err := st.greeting(req)
+			if err != nil {
+				return err
+			}
+			if g != nil {
+				result, err := s.render(greeting.Single, 0, g)
+				if err != nil {
+					return err
+				}
+				s.record(st.Context(), "GreetEveryone", g, result)
+				if len(pending) == bidi.MaxQueued {
+					return status.Errorf(codes.ResourceExhausted, "More than %v greetings are waiting for acknowledgements", bidi.MaxQueued)
+				}
+				pending = append(pending, &greetpb.GreetEveryoneResponse{
+					Result: result,
+				})
+			}
+			req = nil
+		}
+
+		for len(pending) > 0 && st.window.Open() {
+			res := pending[0]
+			pending = pending[1:]
+			res.Sequence = st.window.Next()
+			if err := st.Send(res); err != nil {
+				return err
+			}
+		}
+		if st.reqs == nil && len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case r := <-st.reqs:
+			req = r.(*greetpb.GreetEveryoneRequest)
+		case err := <-st.recvErr:
+			if err != io.EOF {
+				return err
+			}
+			st.stopReceiving()
+		}
+	}
+}
+
+// chat joins the client to the room called name, under the name of the first
+// person it greets, and broadcasts its greetings to everyone in the room.
+// Events of the room are not taken while the window of the client is full,
+// so a client that stops acknowledging them eventually falls behind.
+func (s *Server) chat(st *everyone, name string, first *greetpb.GreetEveryoneRequest) error {
+	participant := strings.TrimSpace(first.GetGreeting().GetFirstName() + " " + first.GetGreeting().GetLastName())
+	m := s.rooms.Join(name, participant)
+	defer m.Leave()
+
+	req := first
+	for {
+		if req != nil {
+			g, err := st.greeting(req)
+			if err != nil {
+				return err
+			}
+			if g != nil {
+				result, err := s.render(greeting.Single, 0, g)
+				if err != nil {
+					return err
+				}
+				m.Greet(result)
+				s.record(st.Context(), "GreetEveryone", g, result)
+			}
+			req = nil
+		}
+
+		events := m.Events()
+		if !st.window.Open() {
+			events = nil
+		}
+		select {
+		case e, ok := <-events:
+			if !ok {
+				if m.Dropped() {
+					return status.Errorf(codes.ResourceExhausted, "Disconnected from room %q for falling behind", name)
+				}
+				return nil
+			}
+			err := st.Send(&greetpb.GreetEveryoneResponse{
+				Result:      e.Text,
+				Event:       eventKinds[e.Kind],
+				Room:        e.Room,
+				Participant: e.Participant,
+				Sequence:    st.window.Next(),
+			})
+			if err != nil {
+				return err
+			}
+		case r := <-st.reqs:
+			req = r.(*greetpb.GreetEveryoneRequest)
+		case err := <-st.recvErr:
+			if err != io.EOF {
+				return err
+			}
+			// The client is done greeting: leave the room but still send
+			// the events buffered so far.
+			m.Leave()
+			st.stopReceiving()
+		}
+	}
+}
+
+var eventKinds = map[room.Kind]greetpb.GreetEveryoneResponse_Event{
+	room.Greeting: greetpb.GreetEveryoneResponse_EVENT_GREETING,
+	room.Join:     greetpb.GreetEveryoneResponse_EVENT_JOIN,
+	room.Leave:    greetpb.GreetEveryoneResponse_EVENT_LEAVE,
+}
+
+// defaultWorkDuration is how long GreetWithDeadline works when the request
+// does not say.
+const defaultWorkDuration = 3 * time.Second
+
+func (s *Server) GreetWithDeadline(ctx context.Context, req *greetpb.GreetWithDeadlineRequest) (*greetpb.GreetWithDeadlineResponse, error) {
+	fmt.Printf("GreetWithDeadline function was invoked with %v\n", req)
+	work, err := durationOrDefault(req.GetWorkDuration(), defaultWorkDuration)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid work duration: %v", err)
+	}
+
+	timer := time.NewTimer(work)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			fmt.Println("The deadline was exceeded!")
+			return nil, status.Error(codes.DeadlineExceeded, "The deadline was exceeded")
+		}
+		fmt.Println("The client canceled the request!")
+		return nil, status.Error(codes.Canceled, "The client cancelled the request")
+	case <-timer.C:
+	}
+
+	result, err := s.render(greeting.Single, 0, req.GetGreeting())
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, "GreetWithDeadline", req.GetGreeting(), result)
+	res := &greetpb.GreetWithDeadlineResponse{
+		Result: result,
+	}
+	return res, nil
+}
@@ -0,0 +1,459 @@
+package greetserver_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-grpc/greet/greetpb"
+	"go-grpc/greet/greetserver"
+	"go-grpc/greet/history"
+	"go-grpc/harness"
+	"go-grpc/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// startGreet serves a greet server validating requests like greet_server
+// does, whose time of day is always afternoon.
+func startGreet(t *testing.T) greetpb.GreetServiceClient {
+	t.Helper()
+	return greetpb.NewGreetServiceClient(startGreetConn(t, greetserver.Options{}))
+}
+
+// startGreetConn is startGreet serving a server configured by opts, and
+// returning the connection to it.
+func startGreetConn(t *testing.T, opts greetserver.Options) *grpc.ClientConn {
+	t.Helper()
+	catalog := harness.Catalog(t)
+	catalog.Now = func() time.Time {
+		return time.Date(2022, 2, 8, 14, 0, 0, 0, time.UTC)
+	}
+	opts.Greetings = catalog
+	srv := harness.NewGreetServer(t, opts)
+	register := func(s *grpc.Server) {
+		greetpb.RegisterGreetServiceServer(s, srv)
+	}
+	return harness.Start(t, register,
+		grpc.ForceServerCodec(validate.Codec{}),
+		grpc.ChainUnaryInterceptor(validate.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(validate.StreamServerInterceptor()),
+	)
+}
+
+func TestGreet(t *testing.T) {
+	c := startGreet(t)
+	tests := []struct {
+		name     string
+		greeting *greetpb.Greeting
+		want     string
+		code     codes.Code
+	}{
+		{"first name", &greetpb.Greeting{FirstName: "Ann"}, "Good afternoon, Ann", codes.OK},
+		{"full name", &greetpb.Greeting{FirstName: "Ann", LastName: "Lee"}, "Good afternoon, Ann Lee", codes.OK},
+		{"locale", &greetpb.Greeting{FirstName: "Ann", Locale: "fr"}, "Bonjour, Ann", codes.OK},
+		{"no greeting", nil, "", codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := c.Greet(context.Background(), &greetpb.GreetRequest{Greeting: tt.greeting})
+			if status.Code(err) != tt.code {
+				t.Fatalf("Greet() error = %v, want code %v", err, tt.code)
+			}
+			if got := res.GetResult(); got != tt.want {
+				t.Errorf("Greet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// rawCodec sends requests encoded by the test, which unlike the proto codec
+// may hold strings that are not valid UTF-8.
+type rawCodec struct{}
+
+func (rawCodec) Name() string { return "proto" }
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) { return v.([]byte), nil }
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	*v.(*[]byte) = data
+	return nil
+}
+
+func TestGreetInvalidUTF8(t *testing.T) {
+	cc := startGreetConn(t, greetserver.Options{})
+	var greeting []byte
+	greeting = protowire.AppendTag(greeting, 1, protowire.BytesType)
+	greeting = protowire.AppendString(greeting, "Ann\xff")
+	greeting = protowire.AppendTag(greeting, 2, protowire.BytesType)
+	greeting = protowire.AppendString(greeting, "L\xc0e\n")
+	var req []byte
+	req = protowire.AppendTag(req, 1, protowire.BytesType)
+	req = protowire.AppendBytes(req, greeting)
+
+	var res []byte
+	err := cc.Invoke(context.Background(), "/greet.GreetService/Greet", req, &res, grpc.ForceCodec(rawCodec{}))
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Greet() error = %v, want code %v", err, codes.InvalidArgument)
+	}
+	var got []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				got = append(got, v.GetField()+" "+v.GetDescription())
+			}
+		}
+	}
+	want := []string{
+		"greeting.first_name must be valid UTF-8",
+		"greeting.last_name must be valid UTF-8",
+		"greeting.last_name must not contain control characters",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Greet() violations = %q, want %q", got, want)
+	}
+}
+
+func TestGreetManyTimes(t *testing.T) {
+	c := startGreet(t)
+	tests := []struct {
+		name  string
+		count int32
+		want  []string
+		code  codes.Code
+	}{
+		{"three", 3, []string{
+			"Good afternoon, Ann number 0",
+			"Good afternoon, Ann number 1",
+			"Good afternoon, Ann number 2",
+		}, codes.OK},
+		{"negative count", -1, nil, codes.InvalidArgument},
+		{"count over the maximum", 1001, nil, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.GreetManyTimes(context.Background(), &greetpb.GreetManyTimesRequest{
+				Greeting: &greetpb.Greeting{FirstName: "Ann"},
+				Count:    tt.count,
+				Interval: durationpb.New(time.Millisecond),
+			})
+			if err != nil {
+				t.Fatalf("GreetManyTimes() error = %v", err)
+			}
+			var got []string
+			for {
+				res, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					if status.Code(err) != tt.code {
+						t.Fatalf("Recv() error = %v, want code %v", err, tt.code)
+					}
+					return
+				}
+				got = append(got, res.GetResult())
+			}
+			if tt.code != codes.OK {
+				t.Fatalf("GreetManyTimes() succeeded, want code %v", tt.code)
+			}
+			if !equal(got, tt.want) {
+				t.Errorf("GreetManyTimes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLongGreet(t *testing.T) {
+	c := startGreet(t)
+	tests := []struct {
+		name  string
+		names []string
+		want  string
+	}{
+		{"one", []string{"Ann"}, "Good afternoon, Ann!"},
+		{"several", []string{"Ann", "Bob", "Cy"}, "Good afternoon, Ann, Bob, Cy!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.LongGreet(context.Background())
+			if err != nil {
+				t.Fatalf("LongGreet() error = %v", err)
+			}
+			for _, name := range tt.names {
+				if err := stream.Send(&greetpb.LongGreetRequest{Greeting: &greetpb.Greeting{FirstName: name}}); err != nil {
+					t.Fatalf("Send() error = %v", err)
+				}
+			}
+			res, err := stream.CloseAndRecv()
+			if err != nil {
+				t.Fatalf("CloseAndRecv() error = %v", err)
+			}
+			if got := res.GetResult(); got != tt.want {
+				t.Errorf("LongGreet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGreetEveryone(t *testing.T) {
+	c := startGreet(t)
+	tests := []struct {
+		name     string
+		requests []*greetpb.GreetEveryoneRequest
+		want     []string
+		code     codes.Code
+	}{
+		{"echo", []*greetpb.GreetEveryoneRequest{
+			{Greeting: &greetpb.Greeting{FirstName: "Ann"}},
+			{Greeting: &greetpb.Greeting{FirstName: "Bob"}},
+		}, []string{"Good afternoon, Ann", "Good afternoon, Bob"}, codes.OK},
+		{"room", []*greetpb.GreetEveryoneRequest{
+			{Greeting: &greetpb.Greeting{FirstName: "Ann"}, Room: "lobby"},
+			{Greeting: &greetpb.Greeting{FirstName: "Bob"}},
+		}, []string{"Ann joined the room", "Good afternoon, Ann", "Good afternoon, Bob"}, codes.OK},
+		{"no greeting", []*greetpb.GreetEveryoneRequest{{}}, nil, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.GreetEveryone(context.Background())
+			if err != nil {
+				t.Fatalf("GreetEveryone() error = %v", err)
+			}
+			for _, req := range tt.requests {
+				if err := stream.Send(req); err != nil {
+					t.Fatalf("Send() error = %v", err)
+				}
+			}
+			stream.CloseSend()
+
+			var got []string
+			for {
+				res, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					if status.Code(err) != tt.code {
+						t.Fatalf("Recv() error = %v, want code %v", err, tt.code)
+					}
+					return
+				}
+				got = append(got, res.GetResult())
+			}
+			if tt.code != codes.OK {
+				t.Fatalf("GreetEveryone() succeeded, want code %v", tt.code)
+			}
+			if !equal(got, tt.want) {
+				t.Errorf("GreetEveryone() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGreetWithDeadline(t *testing.T) {
+	c := startGreet(t)
+	tests := []struct {
+		name     string
+		work     time.Duration
+		deadline time.Duration
+		code     codes.Code
+	}{
+		{"in time", 10 * time.Millisecond, time.Second, codes.OK},
+		{"too slow", time.Second, 50 * time.Millisecond, codes.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.deadline)
+			defer cancel()
+			res, err := c.GreetWithDeadline(ctx, &greetpb.GreetWithDeadlineRequest{
+				Greeting:     &greetpb.Greeting{FirstName: "Ann"},
+				WorkDuration: durationpb.New(tt.work),
+			})
+			if status.Code(err) != tt.code {
+				t.Fatalf("GreetWithDeadline() error = %v, want code %v", err, tt.code)
+			}
+			if tt.code == codes.OK && res.GetResult() != "Good afternoon, Ann" {
+				t.Errorf("GreetWithDeadline() = %q, want %q", res.GetResult(), "Good afternoon, Ann")
+			}
+		})
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestListGreetings(t *testing.T) {
+	for _, kind := range []string{history.Bolt, history.SQLite} {
+		t.Run(kind, func(t *testing.T) {
+			store, err := history.Open(kind, filepath.Join(t.TempDir(), "history.db"))
+			if err != nil {
+				t.Fatalf("history.Open() error = %v", err)
+			}
+			defer store.Close()
+			c := greetpb.NewGreetServiceClient(startGreetConn(t, greetserver.Options{History: store}))
+			ctx := context.Background()
+			for _, name := range []string{"Ann", "Bob", "Anna", "Annie", "Carl"} {
+				if _, err := c.Greet(ctx, &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: name}}); err != nil {
+					t.Fatalf("Greet() error = %v", err)
+				}
+			}
+
+			// Pages of two greetings of names starting with "Ann", oldest
+			// first.
+			var got []string
+			token := ""
+			for pages := 0; ; pages++ {
+				if pages == 3 {
+					t.Fatalf("ListGreetings() still returning pages after %q", got)
+				}
+				stream, err := c.ListGreetings(ctx, &greetpb.ListGreetingsRequest{NamePrefix: "Ann", PageSize: 2, PageToken: token})
+				if err != nil {
+					t.Fatalf("ListGreetings() error = %v", err)
+				}
+				token = ""
+				for {
+					res, err := stream.Recv()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						t.Fatalf("Recv() error = %v", err)
+					}
+					got = append(got, res.GetGreeting().GetFirstName())
+					if r := res.GetGreeting(); r.GetRpc() != "Greet" || r.GetResult() != "Good afternoon, "+r.GetFirstName() {
+						t.Errorf("ListGreetings() greeting = %v, want the Greet of %v", r, r.GetFirstName())
+					}
+					token = res.GetNextPageToken()
+				}
+				if token == "" {
+					break
+				}
+			}
+			if want := []string{"Ann", "Anna", "Annie"}; strings.Join(got, " ") != strings.Join(want, " ") {
+				t.Errorf("ListGreetings() names = %q, want %q", got, want)
+			}
+
+			for _, req := range []*greetpb.ListGreetingsRequest{
+				{PageSize: -1},
+				{PageSize: 1001},
+				{PageToken: "not a token"},
+			} {
+				stream, err := c.ListGreetings(ctx, req)
+				if err == nil {
+					_, err = stream.Recv()
+				}
+				if status.Code(err) != codes.InvalidArgument {
+					t.Errorf("ListGreetings(%v) error = %v, want code %v", req, err, codes.InvalidArgument)
+				}
+			}
+		})
+	}
+
+	stream, err := startGreet(t).ListGreetings(context.Background(), &greetpb.ListGreetingsRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ListGreetings() without history error = %v, want code %v", err, codes.FailedPrecondition)
+	}
+}
+
+func TestSubscribeGreetings(t *testing.T) {
+	c := startGreet(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := c.SubscribeGreetings(ctx, &greetpb.SubscribeGreetingsRequest{NamePrefixes: []string{"Al", "An"}})
+	if err != nil {
+		t.Fatalf("SubscribeGreetings() error = %v", err)
+	}
+	names := make(chan string)
+	go func() {
+		defer close(names)
+		for {
+			res, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			select {
+			case names <- res.GetGreeting().GetFirstName():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	greet := func(name string) {
+		if _, err := c.Greet(ctx, &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: name}}); err != nil {
+			t.Fatalf("Greet() error = %v", err)
+		}
+	}
+
+	// Greetings published before the server subscribed are not streamed, so
+	// greet until one is.
+	probe := time.NewTicker(10 * time.Millisecond)
+	defer probe.Stop()
+wait:
+	for {
+		greet("Alpha")
+		select {
+		case <-names:
+			break wait
+		case <-probe.C:
+		}
+	}
+
+	for _, name := range []string{"Ann", "Bob", "Alice", "Carl", "Anna", "Zed"} {
+		greet(name)
+	}
+	var got []string
+	timeout := time.After(5 * time.Second)
+	for len(got) < 3 {
+		select {
+		case name, ok := <-names:
+			if !ok {
+				t.Fatalf("SubscribeGreetings() ended after %q", got)
+			}
+			if name != "Alpha" {
+				got = append(got, name)
+			}
+		case <-timeout:
+			t.Fatalf("SubscribeGreetings() streamed %q, still waiting for more after 5s", got)
+		}
+	}
+	if want := []string{"Ann", "Alice", "Anna"}; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("SubscribeGreetings() names = %q, want %q", got, want)
+	}
+}
+
+func TestSubscribeGreetingsTooManyPrefixes(t *testing.T) {
+	c := startGreet(t)
+	prefixes := make([]string, 101)
+	for i := range prefixes {
+		prefixes[i] = fmt.Sprintf("P%d", i)
+	}
+	stream, err := c.SubscribeGreetings(context.Background(), &greetpb.SubscribeGreetingsRequest{NamePrefixes: prefixes})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SubscribeGreetings() error = %v, want code %v", err, codes.InvalidArgument)
+	}
+}
@@ -0,0 +1,120 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var greetingsBucket = []byte("greetings")
+
+// boltStore keeps greetings in a single bucket keyed by position, so that
+// iterating the bucket visits them in time order.
+type boltStore struct {
+	db *bolt.DB
+}
+
+func openBolt(path string) (*boltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(greetingsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltStore{db: db}, nil
+}
+
+func (s *boltStore) Add(_ context.Context, r Record) error {
+	v, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(greetingsBucket)
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		return b.Put(position{nanos: r.Time.UnixNano(), seq: seq}.key(), v)
+	})
+}
+
+func (s *boltStore) List(ctx context.Context, q Query) ([]Record, string, error) {
+	if q.PageSize <= 0 {
+		q.PageSize = DefaultPageSize
+	}
+	start := position{}.key()
+	if !q.Start.IsZero() {
+		start = position{nanos: q.Start.UnixNano()}.key()
+	}
+	if q.PageToken != "" {
+		p, err := parseToken(q.PageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		// Resume right after the last greeting of the previous page.
+		p.seq++
+		if k := p.key(); bytes.Compare(k, start) > 0 {
+			start = k
+		}
+	}
+
+	var records []Record
+	var next string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(greetingsBucket).Cursor()
+		var last []byte
+		for k, v := c.Seek(start); k != nil; k, v = c.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			var r Record
+			if err := json.Unmarshal(v, &r); err != nil {
+				return err
+			}
+			if !q.End.IsZero() && !r.Time.Before(q.End) {
+				break
+			}
+			if !q.matches(r) {
+				continue
+			}
+			if len(records) == q.PageSize {
+				next = positionFromKey(last).token()
+				break
+			}
+			records = append(records, r)
+			last = k
+		}
+		return nil
+	})
+	return records, next, err
+}
+
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
+
+func (p position) key() []byte {
+	k := make([]byte, 16)
+	// Flipping the sign bit makes negative times sort before positive ones.
+	binary.BigEndian.PutUint64(k, uint64(p.nanos)^(1<<63))
+	binary.BigEndian.PutUint64(k[8:], p.seq)
+	return k
+}
+
+func positionFromKey(k []byte) position {
+	return position{
+		nanos: int64(binary.BigEndian.Uint64(k) ^ (1 << 63)),
+		seq:   binary.BigEndian.Uint64(k[8:]),
+	}
+}
@@ -0,0 +1,113 @@
+// Package history persists the greetings produced by the greet server so that
+// they can be listed later.
+//
+// Two embedded stores are available, BoltDB and SQLite, both keeping
+// greetings ordered by time. Listings are paginated with opaque tokens that
+// resume right after the last greeting of the previous page.
+package history
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Store kinds accepted by Open.
+const (
+	Bolt   = "bolt"
+	SQLite = "sqlite"
+)
+
+// DefaultPageSize is the page size of queries that do not set one.
+const DefaultPageSize = 100
+
+// ErrInvalidPageToken is returned by List for tokens it did not issue.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// Record is one persisted greeting.
+type Record struct {
+	FirstName string
+	LastName  string
+	Time      time.Time
+	// RPC is the name of the method that produced the greeting, e.g. "Greet".
+	RPC string
+	// Peer is the address of the client that was greeted.
+	Peer   string
+	Result string
+}
+
+// Query selects the greetings to list.
+type Query struct {
+	// NamePrefix matches the start of the first name, case-sensitively.
+	NamePrefix string
+	// Start and End bound the greeting time. Start is inclusive, End is
+	// exclusive and zero values mean unbounded.
+	Start time.Time
+	End   time.Time
+	// PageSize is the maximum number of greetings to return, DefaultPageSize
+	// if not positive.
+	PageSize int
+	// PageToken resumes a previous listing.
+	PageToken string
+}
+
+// Store persists greetings. Implementations are safe for concurrent use.
+type Store interface {
+	// Add persists r.
+	Add(ctx context.Context, r Record) error
+	// List returns the greetings matching q in time order, along with the
+	// token of the next page, which is empty once nothing is left.
+	List(ctx context.Context, q Query) ([]Record, string, error)
+	Close() error
+}
+
+// Open opens the store of the given kind at path, creating it if needed.
+func Open(kind, path string) (Store, error) {
+	switch kind {
+	case Bolt:
+		return openBolt(path)
+	case SQLite:
+		return openSQLite(path)
+	}
+	return nil, fmt.Errorf("unknown history store %q, want %q or %q", kind, Bolt, SQLite)
+}
+
+// position identifies a greeting within the time order. seq breaks ties
+// between greetings recorded in the same nanosecond.
+type position struct {
+	nanos int64
+	seq   uint64
+}
+
+func (p position) token() string {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b, uint64(p.nanos))
+	binary.BigEndian.PutUint64(b[8:], p.seq)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func parseToken(t string) (position, error) {
+	b, err := base64.RawURLEncoding.DecodeString(t)
+	if err != nil || len(b) != 16 {
+		return position{}, ErrInvalidPageToken
+	}
+	return position{
+		nanos: int64(binary.BigEndian.Uint64(b)),
+		seq:   binary.BigEndian.Uint64(b[8:]),
+	}, nil
+}
+
+// matches reports whether r passes the filters of q other than pagination.
+func (q Query) matches(r Record) bool {
+	if !strings.HasPrefix(r.FirstName, q.NamePrefix) {
+		return false
+	}
+	if !q.Start.IsZero() && r.Time.Before(q.Start) {
+		return false
+	}
+	return q.End.IsZero() || r.Time.Before(q.End)
+}
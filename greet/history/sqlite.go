@@ -0,0 +1,106 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	// Registers the "sqlite3" database/sql driver.
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS greetings (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	time       INTEGER NOT NULL,
+	first_name TEXT NOT NULL,
+	last_name  TEXT NOT NULL,
+	rpc        TEXT NOT NULL,
+	peer       TEXT NOT NULL,
+	result     TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS greetings_time ON greetings (time, id);
+`
+
+// sqliteStore keeps greetings in a table ordered by time and row id.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+func openSQLite(path string) (*sqliteStore, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer, so serialise access instead of failing
+	// with "database is locked".
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqliteStore{db: db}, nil
+}
+
+func (s *sqliteStore) Add(ctx context.Context, r Record) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO greetings (time, first_name, last_name, rpc, peer, result) VALUES (?, ?, ?, ?, ?, ?)`,
+		r.Time.UnixNano(), r.FirstName, r.LastName, r.RPC, r.Peer, r.Result)
+	return err
+}
+
+func (s *sqliteStore) List(ctx context.Context, q Query) ([]Record, string, error) {
+	if q.PageSize <= 0 {
+		q.PageSize = DefaultPageSize
+	}
+	query := `SELECT id, time, first_name, last_name, rpc, peer, result FROM greetings
+		WHERE substr(first_name, 1, length(?)) = ?`
+	args := []interface{}{q.NamePrefix, q.NamePrefix}
+	if !q.Start.IsZero() {
+		query += ` AND time >= ?`
+		args = append(args, q.Start.UnixNano())
+	}
+	if !q.End.IsZero() {
+		query += ` AND time < ?`
+		args = append(args, q.End.UnixNano())
+	}
+	if q.PageToken != "" {
+		p, err := parseToken(q.PageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		query += ` AND (time > ? OR (time = ? AND id > ?))`
+		args = append(args, p.nanos, p.nanos, int64(p.seq))
+	}
+	query += ` ORDER BY time, id LIMIT ?`
+	args = append(args, q.PageSize+1)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var records []Record
+	var last position
+	var next string
+	for rows.Next() {
+		if len(records) == q.PageSize {
+			next = last.token()
+			break
+		}
+		var r Record
+		var id, nanos int64
+		if err := rows.Scan(&id, &nanos, &r.FirstName, &r.LastName, &r.RPC, &r.Peer, &r.Result); err != nil {
+			return nil, "", err
+		}
+		r.Time = time.Unix(0, nanos)
+		records = append(records, r)
+		last = position{nanos: nanos, seq: uint64(id)}
+	}
+	return records, next, rows.Err()
+}
+
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
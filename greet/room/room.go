@@ -0,0 +1,147 @@
+// Package room broadcasts events to the members of named rooms.
+//
+// Every member has a bounded buffer of events. Broadcasting never blocks:
+// a member whose buffer is full when an event arrives has fallen behind and
+// is dropped from its room instead.
+package room
+
+import "sync"
+
+// Kind says what happened in a room.
+type Kind int
+
+const (
+	Greeting Kind = iota
+	Join
+	Leave
+)
+
+// Event is something that happened in a room.
+type Event struct {
+	Kind Kind
+	Room string
+	// Participant is the name of the member the event is about.
+	Participant string
+	// Text is the rendered greeting, or a description of the join or leave.
+	Text string
+}
+
+// Hub holds the rooms. Rooms are created by their first member joining and
+// removed once their last member leaves.
+type Hub struct {
+	buffer int
+
+	mu    sync.Mutex
+	rooms map[string]map[*Member]bool
+}
+
+// NewHub returns a hub giving every member a buffer of buffer events.
+func NewHub(buffer int) *Hub {
+	return &Hub{
+		buffer: buffer,
+		rooms:  make(map[string]map[*Member]bool),
+	}
+}
+
+// Member is a participant of a room.
+type Member struct {
+	hub    *Hub
+	room   string
+	name   string
+	events chan Event
+	// dropped is set, with hub.mu held, if the member fell behind.
+	dropped bool
+}
+
+// Join adds a member called name to room and announces it to everyone in
+// the room, the new member included.
+func (h *Hub) Join(room, name string) *Member {
+	m := &Member{
+		hub:    h,
+		room:   room,
+		name:   name,
+		events: make(chan Event, h.buffer),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	members, ok := h.rooms[room]
+	if !ok {
+		members = make(map[*Member]bool)
+		h.rooms[room] = members
+	}
+	members[m] = true
+	h.broadcast(Event{Kind: Join, Room: room, Participant: name, Text: name + " joined the room"})
+	return m
+}
+
+// Events returns the events of the member's room. The channel is closed once
+// the member leaves or is dropped.
+func (m *Member) Events() <-chan Event {
+	return m.events
+}
+
+// Dropped reports whether the member was removed from its room for falling
+// behind. It is only meaningful once Events is closed.
+func (m *Member) Dropped() bool {
+	m.hub.mu.Lock()
+	defer m.hub.mu.Unlock()
+	return m.dropped
+}
+
+// Greet broadcasts a greeting from the member to its room. It does nothing
+// once the member has left.
+func (m *Member) Greet(text string) {
+	h := m.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.rooms[m.room][m] {
+		return
+	}
+	h.broadcast(Event{Kind: Greeting, Room: m.room, Participant: m.name, Text: text})
+}
+
+// Leave removes the member from its room and announces it to the others.
+// It is safe to call more than once.
+func (m *Member) Leave() {
+	h := m.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.rooms[m.room][m] {
+		return
+	}
+	h.remove(m)
+	h.broadcast(Event{Kind: Leave, Room: m.room, Participant: m.name, Text: m.name + " left the room"})
+}
+
+// broadcast delivers e to every member of e.Room, dropping those with a full
+// buffer. h.mu must be held.
+func (h *Hub) broadcast(e Event) {
+	var slow []*Member
+	for m := range h.rooms[e.Room] {
+		select {
+		case m.events <- e:
+		default:
+			slow = append(slow, m)
+		}
+	}
+	for _, m := range slow {
+		m.dropped = true
+		h.remove(m)
+	}
+	// Announcing the dropped members may drop more of them, but every round
+	// leaves the room smaller so this ends.
+	for _, m := range slow {
+		h.broadcast(Event{Kind: Leave, Room: e.Room, Participant: m.name, Text: m.name + " was disconnected for falling behind"})
+	}
+}
+
+// remove takes m out of its room and closes its events. h.mu must be held.
+func (h *Hub) remove(m *Member) {
+	members := h.rooms[m.room]
+	delete(members, m)
+	if len(members) == 0 {
+		delete(h.rooms, m.room)
+	}
+	close(m.events)
+}
@@ -0,0 +1,96 @@
+// Package harness runs the greet and calculator services in-process for
+// tests.
+//
+// Servers listen on an in-memory bufconn listener, so tests need no network
+// and no free port, and are stopped when the test ends.
+package harness
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"go-grpc/calculator/calculatorpb"
+	"go-grpc/calculator/calculatorserver"
+	"go-grpc/greet/greeting"
+	"go-grpc/greet/greetpb"
+	"go-grpc/greet/greetserver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufSize = 1 << 20
+
+// Start starts a server with opts, lets register add services to it and
+// returns a connection to it. Both are closed when the test ends.
+func Start(t testing.TB, register func(*grpc.Server), opts ...grpc.ServerOption) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(bufSize)
+	s := grpc.NewServer(opts...)
+	register(s)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	cc, err := grpc.Dial("bufconn",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Could not connect to the bufconn server: %v", err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return cc
+}
+
+// StartGreet serves srv and returns a client of it. A nil srv serves
+// NewGreetServer(t, greetserver.Options{}).
+func StartGreet(t testing.TB, srv greetpb.GreetServiceServer, opts ...grpc.ServerOption) greetpb.GreetServiceClient {
+	t.Helper()
+	if srv == nil {
+		srv = NewGreetServer(t, greetserver.Options{})
+	}
+	cc := Start(t, func(s *grpc.Server) {
+		greetpb.RegisterGreetServiceServer(s, srv)
+	}, opts...)
+	return greetpb.NewGreetServiceClient(cc)
+}
+
+// StartCalculator serves srv and returns a client of it. A nil srv serves
+// calculatorserver.New(calculatorserver.Options{}).
+func StartCalculator(t testing.TB, srv calculatorpb.CalculatorServiceServer, opts ...grpc.ServerOption) calculatorpb.CalculatorServiceClient {
+	t.Helper()
+	if srv == nil {
+		srv = calculatorserver.New(calculatorserver.Options{})
+	}
+	cc := Start(t, func(s *grpc.Server) {
+		calculatorpb.RegisterCalculatorServiceServer(s, srv)
+	}, opts...)
+	return calculatorpb.NewCalculatorServiceClient(cc)
+}
+
+// NewGreetServer returns a greet server configured by opts, rendering
+// greetings with Catalog(t) unless opts sets another catalog.
+func NewGreetServer(t testing.TB, opts greetserver.Options) *greetserver.Server {
+	t.Helper()
+	if opts.Greetings == nil {
+		opts.Greetings = Catalog(t)
+	}
+	return greetserver.New(opts)
+}
+
+// Catalog loads the greeting catalog shipped with greet_server.
+func Catalog(t testing.TB) *greeting.Catalog {
+	t.Helper()
+	// Tests run in the directory of their package, so the catalog is found
+	// relative to this file instead.
+	_, file, _, _ := runtime.Caller(0)
+	c, err := greeting.LoadCatalog(filepath.Join(filepath.Dir(file), "..", "greet", "greet_server", "locales"))
+	if err != nil {
+		t.Fatalf("Could not load the greeting catalog: %v", err)
+	}
+	return c
+}
@@ -0,0 +1,111 @@
+package loadbalancing
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/resolver"
+)
+
+// FileScheme resolves addresses listed one per line in a file, e.g.
+// "file:///etc/calculator/backends". The file is polled and the address list
+// is pushed to the channel whenever it changes.
+const FileScheme = "file"
+
+// PollInterval is how often file targets are checked for changes.
+var PollInterval = 2 * time.Second
+
+var logger = grpclog.Component("loadbalancing")
+
+func init() {
+	resolver.Register(fileBuilder{})
+}
+
+type fileBuilder struct{}
+
+func (fileBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	r := &fileResolver{
+		path: "/" + strings.TrimPrefix(target.Endpoint, "/"),
+		cc:   cc,
+		now:  make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	if err := r.resolve(); err != nil {
+		return nil, err
+	}
+	r.wg.Add(1)
+	go r.watch()
+	return r, nil
+}
+
+func (fileBuilder) Scheme() string {
+	return FileScheme
+}
+
+type fileResolver struct {
+	path string
+	cc   resolver.ClientConn
+
+	// modTime and size identify the version of the file last pushed to cc.
+	modTime time.Time
+	size    int64
+
+	now  chan struct{}
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func (r *fileResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.now <- struct{}{}:
+	default:
+	}
+}
+
+func (r *fileResolver) Close() {
+	close(r.done)
+	r.wg.Wait()
+}
+
+func (r *fileResolver) watch() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		case <-r.now:
+		}
+		if err := r.resolve(); err != nil {
+			logger.Warningf("resolving %v: %v", r.path, err)
+			r.cc.ReportError(err)
+		}
+	}
+}
+
+// resolve reads the file if it changed since the last call and pushes its
+// addresses to the channel.
+func (r *fileResolver) resolve() error {
+	fi, err := os.Stat(r.path)
+	if err != nil {
+		return err
+	}
+	if fi.ModTime().Equal(r.modTime) && fi.Size() == r.size {
+		return nil
+	}
+	b, err := ioutil.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+	if err := r.cc.UpdateState(resolver.State{Addresses: parseAddresses(strings.Split(string(b), "\n"))}); err != nil {
+		return err
+	}
+	r.modTime, r.size = fi.ModTime(), fi.Size()
+	return nil
+}
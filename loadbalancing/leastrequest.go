@@ -0,0 +1,92 @@
+package loadbalancing
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+)
+
+// LeastRequest is the name of the least-outstanding-requests balancer.
+const LeastRequest = "least_request"
+
+func init() {
+	balancer.Register(leastRequestBuilder{})
+}
+
+// leastRequestBuilder hands every channel its own picker builder so that the
+// outstanding request counts of one channel never leak into another.
+type leastRequestBuilder struct{}
+
+func (leastRequestBuilder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	pb := &lrPickerBuilder{outstanding: make(map[balancer.SubConn]*int64)}
+	return base.NewBalancerBuilder(LeastRequest, pb, base.Config{HealthCheck: true}).Build(cc, opts)
+}
+
+func (leastRequestBuilder) Name() string {
+	return LeastRequest
+}
+
+type lrPickerBuilder struct {
+	mu sync.Mutex
+	// outstanding counts in-flight RPCs per SubConn. It outlives individual
+	// pickers so that counts carry over when the ready set changes.
+	outstanding map[balancer.SubConn]*int64
+}
+
+func (b *lrPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sc := range b.outstanding {
+		if _, ok := info.ReadySCs[sc]; !ok {
+			delete(b.outstanding, sc)
+		}
+	}
+	p := &lrPicker{}
+	for sc := range info.ReadySCs {
+		n, ok := b.outstanding[sc]
+		if !ok {
+			n = new(int64)
+			b.outstanding[sc] = n
+		}
+		p.backends = append(p.backends, lrBackend{subConn: sc, outstanding: n})
+	}
+	return p
+}
+
+type lrBackend struct {
+	subConn     balancer.SubConn
+	outstanding *int64
+}
+
+// lrPicker sends each RPC to the ready backend with the fewest RPCs in
+// flight. Ties are broken at random so that an idle cluster still spreads
+// load instead of piling onto the first backend.
+type lrPicker struct {
+	backends []lrBackend
+}
+
+func (p *lrPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	start := rand.Intn(len(p.backends))
+	best := p.backends[start]
+	for i := 1; i < len(p.backends); i++ {
+		b := p.backends[(start+i)%len(p.backends)]
+		if atomic.LoadInt64(b.outstanding) < atomic.LoadInt64(best.outstanding) {
+			best = b
+		}
+	}
+
+	atomic.AddInt64(best.outstanding, 1)
+	return balancer.PickResult{
+		SubConn: best.subConn,
+		Done: func(balancer.DoneInfo) {
+			atomic.AddInt64(best.outstanding, -1)
+		},
+	}, nil
+}
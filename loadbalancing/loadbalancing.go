@@ -0,0 +1,26 @@
+// Package loadbalancing lets clients spread RPCs across several server
+// replicas.
+//
+// Importing the package registers the "static" and "file" resolvers and the
+// least_request balancer with grpc-go, next to its built-in "dns" resolver
+// and round_robin balancer. It also registers the client side of the
+// standard health checking protocol, so balancers skip replicas whose health
+// service reports them as not serving.
+package loadbalancing
+
+import (
+	"strings"
+
+	// Registers the client health checking function used by the balancers.
+	_ "google.golang.org/grpc/health"
+)
+
+// Target turns a user-supplied address into a dial target. Targets that
+// already name a scheme ("dns:///", "static:///", "file:///") are returned
+// unchanged and a comma-separated list of addresses becomes a static target.
+func Target(addr string) string {
+	if strings.Contains(addr, ":///") || !strings.Contains(addr, ",") {
+		return addr
+	}
+	return StaticScheme + ":///" + addr
+}
@@ -0,0 +1,54 @@
+package loadbalancing
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// StaticScheme resolves a fixed, comma-separated list of addresses, e.g.
+// "static:///localhost:50051,localhost:50052".
+const StaticScheme = "static"
+
+func init() {
+	resolver.Register(staticBuilder{})
+}
+
+type staticBuilder struct{}
+
+func (staticBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	err := cc.UpdateState(resolver.State{Addresses: parseAddresses(strings.Split(target.Endpoint, ","))})
+	if err != nil {
+		return nil, err
+	}
+	return staticResolver{}, nil
+}
+
+func (staticBuilder) Scheme() string {
+	return StaticScheme
+}
+
+// staticResolver has nothing to do after Build since its addresses never
+// change.
+type staticResolver struct{}
+
+func (staticResolver) ResolveNow(resolver.ResolveNowOptions) {}
+
+func (staticResolver) Close() {}
+
+// parseAddresses turns a list of host:port strings into resolver addresses,
+// skipping blank entries and "#" comments.
+func parseAddresses(lines []string) []resolver.Address {
+	var addrs []resolver.Address
+	for _, l := range lines {
+		if i := strings.Index(l, "#"); i >= 0 {
+			l = l[:i]
+		}
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+		addrs = append(addrs, resolver.Address{Addr: l})
+	}
+	return addrs
+}
@@ -0,0 +1,78 @@
+package loadtest
+
+import (
+	"math/bits"
+	"time"
+)
+
+// subBucketBits sets the precision of a histogram: every power of two is
+// split into 1<<subBucketBits buckets, so that values are reported within
+// 1/64 of what they were, however many are recorded.
+const subBucketBits = 6
+
+// histogram counts durations in buckets of fixed, logarithmically growing
+// widths. Durations under 1<<subBucketBits nanoseconds are counted exactly.
+type histogram struct {
+	counts [(65 - subBucketBits) << subBucketBits]int
+	n      int
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+func (h *histogram) add(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	if h.n == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.n++
+	h.sum += d
+	h.counts[bucketOf(uint64(d))]++
+}
+
+// percentile returns the pth percentile of the durations, using the
+// nearest-rank method. It is the upper bound of the bucket holding it, and
+// no more than the largest duration.
+func (h *histogram) percentile(p int) time.Duration {
+	rank := (h.n*p + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	seen := 0
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank {
+			if d := time.Duration(bucketMax(i)); d < h.max {
+				return d
+			}
+			break
+		}
+	}
+	return h.max
+}
+
+// bucketOf returns the index of the bucket counting v. Values up to
+// 1<<subBucketBits are their own bucket, larger ones share a bucket with
+// those having the same subBucketBits+1 leading bits.
+func bucketOf(v uint64) int {
+	if v < 1<<subBucketBits {
+		return int(v)
+	}
+	shift := bits.Len64(v) - subBucketBits - 1
+	return (shift+1)<<subBucketBits + int(v>>shift) - 1<<subBucketBits
+}
+
+// bucketMax returns the largest value counted by bucket i.
+func bucketMax(i int) uint64 {
+	if i < 1<<subBucketBits {
+		return uint64(i)
+	}
+	shift := i>>subBucketBits - 1
+	lead := uint64(i&(1<<subBucketBits-1) + 1<<subBucketBits)
+	return lead<<shift + 1<<shift - 1
+}
@@ -0,0 +1,140 @@
+// Package loadtest drives a server with concurrent workers for a fixed time,
+// optionally at a fixed rate, and reports the throughput, latencies and
+// errors observed.
+//
+// What a worker does is up to the caller: an operation may be a unary call,
+// a whole streaming call, or one round trip of a stream kept open by the
+// worker, so that Concurrency is then the number of concurrent streams.
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Worker performs operations for one of the concurrent workers of a run. It
+// is only used by one goroutine at a time.
+type Worker interface {
+	// Do performs one operation and returns the error it failed with.
+	Do(ctx context.Context) error
+	// Close releases the worker, e.g. ends its stream.
+	Close() error
+}
+
+// NewWorker returns a new worker, e.g. opening its stream.
+type NewWorker func(ctx context.Context) (Worker, error)
+
+// Options configures a run.
+type Options struct {
+	// Name labels the report, e.g. with the method called.
+	Name string
+	// Concurrency is the number of workers, defaulting to 1.
+	Concurrency int
+	// Rate is the number of operations per second started across all
+	// workers, 0 to start them as fast as the workers allow.
+	Rate float64
+	// Duration is how long operations are started for.
+	Duration time.Duration
+}
+
+// Run runs the workers made by newWorker until opts.Duration has passed or
+// ctx is done, and reports on the operations they performed. It fails only
+// if no worker could be made.
+func Run(ctx context.Context, opts Options, newWorker NewWorker) (*Report, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.Duration <= 0 {
+		return nil, fmt.Errorf("duration must be positive")
+	}
+
+	// Workers are made with the context of the run, so that their streams
+	// and any operation in flight end with it.
+	ctx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+	workers := make([]Worker, 0, opts.Concurrency)
+	defer func() {
+		for _, w := range workers {
+			w.Close()
+		}
+	}()
+	s := newStats()
+	for i := 0; i < opts.Concurrency; i++ {
+		w, err := newWorker(ctx)
+		if err != nil {
+			// Servers refusing more streams is a finding, not a failure, as
+			// long as some could be opened.
+			s.startFailed(err)
+			continue
+		}
+		workers = append(workers, w)
+	}
+	if len(workers) == 0 {
+		return nil, fmt.Errorf("could not start any worker: %v", s.firstErr)
+	}
+
+	p := newPacer(opts.Rate)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, w := range workers {
+		wg.Add(1)
+		go func(w Worker) {
+			defer wg.Done()
+			for p.wait(ctx) {
+				t := time.Now()
+				err := w.Do(ctx)
+				if err != nil && ctx.Err() != nil {
+					// Cut short by the end of the run.
+					return
+				}
+				s.add(time.Since(t), err)
+			}
+		}(w)
+	}
+	wg.Wait()
+	return s.report(opts, len(workers), time.Since(start)), nil
+}
+
+// pacer spaces the starts of operations to keep to a rate.
+type pacer struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newPacer(rate float64) *pacer {
+	p := &pacer{next: time.Now()}
+	if rate > 0 {
+		p.interval = time.Duration(float64(time.Second) / rate)
+	}
+	return p
+}
+
+// wait blocks until the next operation may start, reporting false if ctx is
+// done first.
+func (p *pacer) wait(ctx context.Context) bool {
+	if p.interval == 0 {
+		return ctx.Err() == nil
+	}
+	p.mu.Lock()
+	now := time.Now()
+	if p.next.Before(now) {
+		// Workers that fell behind do not get to catch up in a burst.
+		p.next = now
+	}
+	at := p.next
+	p.next = p.next.Add(p.interval)
+	p.mu.Unlock()
+
+	t := time.NewTimer(time.Until(at))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"go-grpc/loadtest"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+)
+
+var (
+	addr        = flag.String("addr", "localhost:50051", "address of the greet or calculator server to load")
+	method      = flag.String("method", "Greet", "name of the RPC to call, e.g. Greet or CalculateStreamingMax")
+	concurrency = flag.Int("concurrency", 10, "number of concurrent callers; for GreetEveryone, CalculateStreamingMax and CalculateStream the number of open streams, each operation being one round trip on a stream")
+	rate        = flag.Float64("rate", 0, "operations started per second across all callers, 0 for as many as the server sustains")
+	duration    = flag.Duration("duration", 10*time.Second, "how long to run for")
+	payloadSize = flag.Int("payload_size", 16, "number of characters of the names greeted, split between the first and last names; the server rejects either over 100 characters, so sizes over 200 fail every greet call with InvalidArgument")
+	messages    = flag.Int("messages", 10, "number of messages of each streaming call, items of each CalculateBatch call or entries of each page listed")
+	number      = flag.Int("number", 360, "number added, averaged, square-rooted or factorised by calculator calls")
+	interval    = flag.Duration("interval", 0, "GreetManyTimes interval between responses and GreetWithDeadline work duration")
+	format      = flag.String("format", "text", "format of the report: text or json")
+)
+
+func main() {
+	flag.Parse()
+
+	m, ok := methods[*method]
+	if !ok {
+		log.Fatalf("Unknown method %q, want one of %v", *method, methodNames())
+	}
+	if *format != "text" && *format != "json" {
+		log.Fatalf("Unknown report format %q, want text or json", *format)
+	}
+
+	cc, err := grpc.Dial(*addr, grpc.WithInsecure())
+	if err != nil {
+		log.Fatalf("Could not connect: %v", err)
+	}
+	defer cc.Close()
+
+	// Interrupting the run still reports on the operations performed so far.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	log.Printf("Calling %v for %v with %d callers", *method, *duration, *concurrency)
+	newWorker := m(cc, params{
+		payloadSize: *payloadSize,
+		messages:    *messages,
+		number:      int32(*number),
+		interval:    durationpb.New(*interval),
+	})
+	r, err := loadtest.Run(ctx, loadtest.Options{
+		Name:        *method,
+		Concurrency: *concurrency,
+		Rate:        *rate,
+		Duration:    *duration,
+	}, newWorker)
+	if err != nil {
+		log.Fatalf("Load test failed: %v", err)
+	}
+
+	if *format == "json" {
+		err = r.WriteJSON(os.Stdout)
+	} else {
+		err = r.WriteText(os.Stdout)
+	}
+	if err != nil {
+		log.Fatalf("Could not write report: %v", err)
+	}
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go-grpc/calculator/calculatorpb"
+	"go-grpc/greet/greetpb"
+	"go-grpc/loadtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"io"
+	"sort"
+	"strings"
+)
+
+// params shapes the requests of the operations.
+type params struct {
+	// payloadSize is the number of characters of the names greeted.
+	payloadSize int
+	// messages is the number of messages of each streaming call, or of the
+	// items of a batch.
+	messages int
+	// number is the number calculated on.
+	number int32
+	// interval is the GreetManyTimes interval and GreetWithDeadline work
+	// duration.
+	interval *durationpb.Duration
+}
+
+// call is a worker whose every operation is a whole call.
+type call func(ctx context.Context) error
+
+func (c call) Do(ctx context.Context) error {
+	return c(ctx)
+}
+
+func (c call) Close() error {
+	return nil
+}
+
+// exchange is a bidi stream of a roundTrip worker.
+type exchange struct {
+	send func() error
+	recv func() error
+}
+
+// roundTrip is a worker keeping a bidi stream open, whose every operation
+// sends a message and receives the response to it. A stream that fails is
+// replaced by a new one on the next operation, so that it only counts as one
+// failed operation.
+type roundTrip struct {
+	open func(ctx context.Context) (*exchange, error)
+
+	stream *exchange
+	cancel context.CancelFunc
+}
+
+// newRoundTrip returns a roundTrip worker, opening its first stream with
+// open.
+func newRoundTrip(open func(ctx context.Context) (*exchange, error)) loadtest.NewWorker {
+	return func(ctx context.Context) (loadtest.Worker, error) {
+		r := &roundTrip{open: open}
+		if err := r.reopen(ctx); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+}
+
+func (r *roundTrip) reopen(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := r.open(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	r.stream, r.cancel = stream, cancel
+	return nil
+}
+
+func (r *roundTrip) Do(ctx context.Context) error {
+	if r.stream == nil {
+		if err := r.reopen(ctx); err != nil {
+			return err
+		}
+	}
+	err := r.stream.send()
+	if err == io.EOF {
+		// The server ended the stream, and Recv returns its status.
+		err = nil
+	}
+	if err == nil {
+		err = r.stream.recv()
+	}
+	if err == io.EOF {
+		err = errStreamEnded
+	}
+	if err != nil {
+		r.Close()
+	}
+	return err
+}
+
+func (r *roundTrip) Close() error {
+	if r.stream == nil {
+		return nil
+	}
+	// Cancelling rather than closing the sending side ends the stream
+	// without waiting for the server.
+	r.cancel()
+	r.stream, r.cancel = nil, nil
+	return nil
+}
+
+// errStreamEnded is the failure of a round trip whose stream the server
+// ended successfully before responding.
+var errStreamEnded = status.Error(codes.Unknown, "stream ended by the server")
+
+// rpc makes the workers of an RPC.
+type rpc func(cc *grpc.ClientConn, p params) loadtest.NewWorker
+
+var methods = map[string]rpc{
+	"Greet": func(cc *grpc.ClientConn, p params) loadtest.NewWorker {
+		c := greetpb.NewGreetServiceClient(cc)
+		req := &greetpb.GreetRequest{Greeting: greeting(p)}
+		return newCall(func(ctx context.Context) error {
+			_, err := c.Greet(ctx, req)
+			return err
+		})
+	},
+	"GreetManyTimes": func(cc *grpc.ClientConn, p params) loadtest.NewWorker {
+		c := greetpb.NewGreetServiceClient(cc)
+		req := &greetpb.GreetManyTimesRequest{Greeting: greeting(p), Count: int32(p.messages), Interval: p.interval}
+		return newCall(func(ctx context.Context) error {
+			stream, err := c.GreetManyTimes(ctx, req)
+			if err != nil {
+				return err
+			}
+			return drain(func() error {
+				_, err := stream.Recv()
+				return err
+			})
+		})
+	},
+	"LongGreet": func(cc *grpc.ClientConn, p params) loadtest.NewWorker {
+		c := greetpb.NewGreetServiceClient(cc)
+		req := &greetpb.LongGreetRequest{Greeting: greeting(p)}
+		return newCall(func(ctx context.Context) error {
+			stream, err := c.LongGreet(ctx)
+			if err != nil {
+				return err
+			}
+			for i := 0; i < p.messages; i++ {
+				if err := stream.Send(req); err != nil {
+					break
+				}
+			}
+			_, err = stream.CloseAndRecv()
+			return err
+		})
+	},
+	"GreetEveryone": func(cc *grpc.ClientConn, p params) loadtest.NewWorker {
+		c := greetpb.NewGreetServiceClient(cc)
+		req := &greetpb.GreetEveryoneRequest{Greeting: greeting(p)}
+		return newRoundTrip(func(ctx context.Context) (*exchange, error) {
+			stream, err := c.GreetEveryone(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return &exchange{
+				send: func() error { return stream.Send(req) },
+				recv: func() error {
+					_, err := stream.Recv()
+					return err
+				},
+			}, nil
+		})
+	},
+	"GreetWithDeadline": func(cc *grpc.ClientConn, p params) loadtest.NewWorker {
+		c := greetpb.NewGreetServiceClient(cc)
+		req := &greetpb.GreetWithDeadlineRequest{Greeting: greeting(p), WorkDuration: p.interval}
+		return newCall(func(ctx context.Context) error {
+			_, err := c.GreetWithDeadline(ctx, req)
+			return err
+		})
+	},
+	"ListGreetings": func(cc *grpc.ClientConn, p params) loadtest.NewWorker {
+		c := greetpb.NewGreetServiceClient(cc)
+		req := &greetpb.ListGreetingsRequest{PageSize: int32(p.messages)}
+		return newCall(func(ctx context.Context) error {
+			stream, err := c.ListGreetings(ctx, req)
+			if err != nil {
+				return err
+			}
+			return drain(func() error {
+				_, err := stream.Recv()
+				return err
+			})
+		})
+	},
+	"Calculate": func(cc *grpc.ClientConn, p params) loadtest.NewWorker {
+		c := calculatorpb.NewCalculatorServiceClient(cc)
+		req := &calculatorpb.CalculatorRequest{X: p.number, Y: p.number}
+		return newCall(func(ctx context.Context) error {
+			_, err := c.Calculate(ctx, req)
+			return err
+		})
+	},
+	"CalculatePrimeStreaming": func(cc *grpc.ClientConn, p params) loadtest.NewWorker {
+		c := calculatorpb.NewCalculatorServiceClient(cc)
+		req := &calculatorpb.CalculatorStreamingRequest{X: p.number}
+		return newCall(func(ctx context.Context) error {
+			stream, err := c.CalculatePrimeStreaming(ctx, req)
+			if err != nil {
+				return err
+			}
+			return drain(func() error {
+				_, err := stream.Recv()
+				return err
+			})
+		})
+	},
+	"CalculateAverage": func(cc *grpc.ClientConn, p params) loadtest.NewWorker {
+		c := calculatorpb.NewCalculatorServiceClient(cc)
+		req := &calculatorpb.CalculatorStreamingRequest{X: p.number}
+		return newCall(func(ctx context.Context) error {
+			stream, err := c.CalculateAverage(ctx)
+			if err != nil {
+				return err
+			}
+			for i := 0; i < p.messages; i++ {
+				if err := stream.Send(req); err != nil {
+					break
+				}
+			}
+			_, err = stream.CloseAndRecv()
+			return err
+		})
+	},
+	"CalculateStreamingMax": func(cc *grpc.ClientConn, p params) loadtest.NewWorker {
+		c := calculatorpb.NewCalculatorServiceClient(cc)
+		return newRoundTrip(func(ctx context.Context) (*exchange, error) {
+			stream, err := c.CalculateStreamingMax(ctx)
+			if err != nil {
+				return nil, err
+			}
+			// Only a new maximum is responded to, so every number sent is
+			// larger than the last.
+			var x int32
+			return &exchange{
+				send: func() error {
+					x++
+					return stream.Send(&calculatorpb.CalculatorStreamingRequest{X: x})
+				},
+				recv: func() error {
+					_, err := stream.Recv()
+					return err
+				},
+			}, nil
+		})
+	},
+	"SquareRoot": func(cc *grpc.ClientConn, p params) loadtest.NewWorker {
+		c := calculatorpb.NewCalculatorServiceClient(cc)
+		req := &calculatorpb.SquareRootRequest{Number: p.number}
+		return newCall(func(ctx context.Context) error {
+			_, err := c.SquareRoot(ctx, req)
+			return err
+		})
+	},
+	"CalculateBatch": func(cc *grpc.ClientConn, p params) loadtest.NewWorker {
+		c := calculatorpb.NewCalculatorServiceClient(cc)
+		req := &calculatorpb.CalculateBatchRequest{}
+		for i := 0; i < p.messages; i++ {
+			req.Requests = append(req.Requests, &calculatorpb.CalculatorRequest{X: p.number, Y: int32(i)})
+		}
+		return newCall(func(ctx context.Context) error {
+			_, err := c.CalculateBatch(ctx, req)
+			return err
+		})
+	},
+	"CalculateStream": func(cc *grpc.ClientConn, p params) loadtest.NewWorker {
+		c := calculatorpb.NewCalculatorServiceClient(cc)
+		return newRoundTrip(func(ctx context.Context) (*exchange, error) {
+			stream, err := c.CalculateStream(ctx)
+			if err != nil {
+				return nil, err
+			}
+			// Jobs complete in order as only one is in flight at a time.
+			var id int
+			return &exchange{
+				send: func() error {
+					id++
+					return stream.Send(&calculatorpb.CalculateStreamRequest{
+						CorrelationId: fmt.Sprint(id),
+						Operation:     &calculatorpb.CalculateStreamRequest_PrimeFactors{PrimeFactors: p.number},
+					})
+				},
+				recv: func() error {
+					_, err := stream.Recv()
+					return err
+				},
+			}, nil
+		})
+	},
+	"GetHistory": func(cc *grpc.ClientConn, p params) loadtest.NewWorker {
+		c := calculatorpb.NewCalculatorServiceClient(cc)
+		req := &calculatorpb.GetHistoryRequest{PageSize: int32(p.messages)}
+		return newCall(func(ctx context.Context) error {
+			_, err := c.GetHistory(ctx, req)
+			return err
+		})
+	},
+}
+
+func methodNames() string {
+	var names []string
+	for name := range methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func newCall(c call) loadtest.NewWorker {
+	return func(context.Context) (loadtest.Worker, error) {
+		return c, nil
+	}
+}
+
+// greeting returns a greeting whose names add up to p.payloadSize
+// characters.
+func greeting(p params) *greetpb.Greeting {
+	first := p.payloadSize - p.payloadSize/2
+	return &greetpb.Greeting{
+		FirstName: strings.Repeat("a", first),
+		LastName:  strings.Repeat("b", p.payloadSize-first),
+	}
+}
+
+// drain receives until the stream ends.
+func drain(recv func() error) error {
+	for {
+		err := recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package loadtest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"go-grpc/greet/greetfake"
+	"go-grpc/greet/greetpb"
+	"go-grpc/harness"
+	"go-grpc/loadtest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type greetWorker struct {
+	c greetpb.GreetServiceClient
+}
+
+func (w greetWorker) Do(ctx context.Context) error {
+	_, err := w.c.Greet(ctx, &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "Ann"}})
+	return err
+}
+
+func (w greetWorker) Close() error {
+	return nil
+}
+
+func newGreetWorker(c greetpb.GreetServiceClient) loadtest.NewWorker {
+	return func(context.Context) (loadtest.Worker, error) {
+		return greetWorker{c}, nil
+	}
+}
+
+func TestRun(t *testing.T) {
+	s := greetfake.New()
+	s.On("Greet").Return(&greetpb.GreetResponse{Result: "Hello Ann"}).
+		FailCall(2, status.Error(codes.Unavailable, "down")).
+		FailCall(3, status.Error(codes.Internal, "broken"))
+	c := harness.StartGreet(t, s)
+
+	r, err := loadtest.Run(context.Background(), loadtest.Options{
+		Concurrency: 4,
+		Duration:    100 * time.Millisecond,
+	}, newGreetWorker(c))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if r.Workers != 4 {
+		t.Errorf("Run() workers = %d, want 4", r.Workers)
+	}
+	if r.Operations != r.Succeeded+r.Failed || r.Succeeded == 0 {
+		t.Errorf("Run() operations = %d, succeeded = %d, failed = %d", r.Operations, r.Succeeded, r.Failed)
+	}
+	if r.Failed != 2 || r.Errors["Unavailable"] != 1 || r.Errors["Internal"] != 1 {
+		t.Errorf("Run() failed = %d, errors = %v, want one Unavailable and one Internal", r.Failed, r.Errors)
+	}
+	if l := r.Latency; l.Min <= 0 || l.Min > l.P50 || l.P50 > l.P99 || l.P99 > l.Max {
+		t.Errorf("Run() latency = %+v, want ordered percentiles", l)
+	}
+}
+
+func TestRunRate(t *testing.T) {
+	c := harness.StartGreet(t, nil)
+	r, err := loadtest.Run(context.Background(), loadtest.Options{
+		Concurrency: 4,
+		Rate:        100,
+		Duration:    500 * time.Millisecond,
+	}, newGreetWorker(c))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	// 50 operations are due, the first one immediately.
+	if r.Operations < 40 || r.Operations > 51 {
+		t.Errorf("Run() operations = %d, want about 50", r.Operations)
+	}
+}
+
+func TestRunNoWorker(t *testing.T) {
+	_, err := loadtest.Run(context.Background(), loadtest.Options{Duration: time.Second}, func(context.Context) (loadtest.Worker, error) {
+		return nil, status.Error(codes.ResourceExhausted, "too many streams")
+	})
+	if err == nil {
+		t.Error("Run() succeeded without workers, want an error")
+	}
+}
+
+// TestRunStartFailures checks that workers failing to start are reported
+// apart from the operations.
+func TestRunStartFailures(t *testing.T) {
+	c := harness.StartGreet(t, nil)
+	started := 0
+	r, err := loadtest.Run(context.Background(), loadtest.Options{
+		Concurrency: 4,
+		Duration:    100 * time.Millisecond,
+	}, func(ctx context.Context) (loadtest.Worker, error) {
+		if started == 2 {
+			return nil, status.Error(codes.ResourceExhausted, "too many streams")
+		}
+		started++
+		return newGreetWorker(c)(ctx)
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if r.Workers != 2 || r.StartErrors["ResourceExhausted"] != 2 {
+		t.Errorf("Run() workers = %d, start errors = %v, want 2 and two ResourceExhausted", r.Workers, r.StartErrors)
+	}
+	if r.Failed != 0 || len(r.Errors) != 0 || r.Operations != r.Succeeded {
+		t.Errorf("Run() operations = %d, failed = %d, errors = %v, want no failed operations", r.Operations, r.Failed, r.Errors)
+	}
+}
+
+func TestReport(t *testing.T) {
+	r := &loadtest.Report{
+		Name:        "Greet",
+		Concurrency: 3,
+		Workers:     2,
+		StartErrors: map[string]int{"ResourceExhausted": 1},
+		Duration:    loadtest.Millis(time.Second),
+		Operations:  3,
+		Succeeded:   2,
+		Failed:      1,
+		Throughput:  2,
+		Latency:     loadtest.Latency{P50: loadtest.Millis(1500 * time.Microsecond)},
+		Errors:      map[string]int{"Unavailable": 1},
+	}
+
+	var text bytes.Buffer
+	if err := r.WriteText(&text); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	for _, want := range []string{"Greet", "3 (2 succeeded, 1 failed)", "p50 1.5ms", "2 of 3", "ResourceExhausted  1", "Unavailable  1"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("WriteText() = %q, want it to contain %q", text.String(), want)
+		}
+	}
+
+	var b bytes.Buffer
+	if err := r.WriteJSON(&b); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var got struct {
+		DurationMs  float64            `json:"durationMs"`
+		LatencyMs   map[string]float64 `json:"latencyMs"`
+		Errors      map[string]int     `json:"errors"`
+		StartErrors map[string]int     `json:"startErrors"`
+	}
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("WriteJSON() wrote invalid JSON: %v", err)
+	}
+	if got.DurationMs != 1000 || got.LatencyMs["p50"] != 1.5 || got.Errors["Unavailable"] != 1 || got.StartErrors["ResourceExhausted"] != 1 {
+		t.Errorf("WriteJSON() = %s", b.String())
+	}
+}
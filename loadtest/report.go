@@ -0,0 +1,176 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc/status"
+)
+
+// Report sums up a run.
+type Report struct {
+	Name        string `json:"name,omitempty"`
+	Concurrency int    `json:"concurrency"`
+	// Workers is the number of workers that could be started, which may be
+	// fewer than Concurrency.
+	Workers int `json:"workers"`
+	// StartErrors counts the workers that could not be started by status
+	// code.
+	StartErrors map[string]int `json:"startErrors,omitempty"`
+	Rate        float64        `json:"rate,omitempty"`
+	Duration    Millis         `json:"durationMs"`
+
+	Operations int `json:"operations"`
+	Succeeded  int `json:"succeeded"`
+	Failed     int `json:"failed"`
+	// Throughput is the number of operations that succeeded per second.
+	Throughput float64 `json:"throughput"`
+	// Latency is that of the operations that succeeded. Percentiles are
+	// within 2% of the exact ones.
+	Latency Latency `json:"latencyMs"`
+	// Errors counts the failed operations by status code.
+	Errors map[string]int `json:"errors,omitempty"`
+}
+
+// Latency holds latency statistics.
+type Latency struct {
+	Min  Millis `json:"min"`
+	Mean Millis `json:"mean"`
+	P50  Millis `json:"p50"`
+	P90  Millis `json:"p90"`
+	P95  Millis `json:"p95"`
+	P99  Millis `json:"p99"`
+	Max  Millis `json:"max"`
+}
+
+// Millis is a time.Duration written in JSON as a number of milliseconds.
+type Millis time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (m Millis) MarshalJSON() ([]byte, error) {
+	return json.Marshal(float64(m) / float64(time.Millisecond))
+}
+
+func (m Millis) String() string {
+	return time.Duration(m).String()
+}
+
+// WriteJSON writes r as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteText writes r as a table for people.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	rate := "unlimited"
+	if r.Rate > 0 {
+		rate = fmt.Sprintf("%g/s", r.Rate)
+	}
+	if r.Name != "" {
+		fmt.Fprintf(tw, "Name:\t%v\n", r.Name)
+	}
+	fmt.Fprintf(tw, "Workers:\t%d of %d\n", r.Workers, r.Concurrency)
+	writeCounts(tw, "Start errors", r.StartErrors)
+	fmt.Fprintf(tw, "Rate:\t%v\n", rate)
+	fmt.Fprintf(tw, "Duration:\t%v\n", time.Duration(r.Duration).Round(time.Millisecond))
+	fmt.Fprintf(tw, "Operations:\t%d (%d succeeded, %d failed)\n", r.Operations, r.Succeeded, r.Failed)
+	fmt.Fprintf(tw, "Throughput:\t%.1f/s\n", r.Throughput)
+	fmt.Fprintf(tw, "Latency:\tmin %v\tmean %v\tp50 %v\tp90 %v\tp95 %v\tp99 %v\tmax %v\n",
+		r.Latency.Min, r.Latency.Mean, r.Latency.P50, r.Latency.P90, r.Latency.P95, r.Latency.P99, r.Latency.Max)
+	writeCounts(tw, "Errors", r.Errors)
+	return tw.Flush()
+}
+
+// writeCounts writes the counts of status codes under title, if any.
+func writeCounts(w io.Writer, title string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%v:\n", title)
+	codes := make([]string, 0, len(counts))
+	for c := range counts {
+		codes = append(codes, c)
+	}
+	sort.Strings(codes)
+	for _, c := range codes {
+		fmt.Fprintf(w, "  %v\t%d\n", c, counts[c])
+	}
+}
+
+// stats collects the outcomes of operations.
+type stats struct {
+	mu          sync.Mutex
+	latencies   histogram
+	failures    int
+	errors      map[string]int
+	startErrors map[string]int
+	firstErr    error
+}
+
+func newStats() *stats {
+	return &stats{
+		errors:      make(map[string]int),
+		startErrors: make(map[string]int),
+	}
+}
+
+func (s *stats) add(d time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.failures++
+		s.errors[status.Code(err).String()]++
+		return
+	}
+	s.latencies.add(d)
+}
+
+// startFailed records a worker that could not be started.
+func (s *stats) startFailed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.startErrors[status.Code(err).String()]++
+	if s.firstErr == nil {
+		s.firstErr = err
+	}
+}
+
+func (s *stats) report(opts Options, workers int, elapsed time.Duration) *Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := &s.latencies
+	r := &Report{
+		Name:        opts.Name,
+		Concurrency: opts.Concurrency,
+		Workers:     workers,
+		StartErrors: s.startErrors,
+		Rate:        opts.Rate,
+		Duration:    Millis(elapsed),
+		Operations:  h.n + s.failures,
+		Succeeded:   h.n,
+		Failed:      s.failures,
+		Throughput:  float64(h.n) / elapsed.Seconds(),
+		Errors:      s.errors,
+	}
+	if h.n == 0 {
+		return r
+	}
+	r.Latency = Latency{
+		Min:  Millis(h.min),
+		Mean: Millis(h.sum / time.Duration(h.n)),
+		P50:  Millis(h.percentile(50)),
+		P90:  Millis(h.percentile(90)),
+		P95:  Millis(h.percentile(95)),
+		P99:  Millis(h.percentile(99)),
+		Max:  Millis(h.max),
+	}
+	return r
+}
@@ -0,0 +1,33 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// bucket is a token bucket refilled at rate tokens per second up to burst
+// tokens. It is not safe for concurrent use.
+type bucket struct {
+	key    bucketKey
+	tokens float64
+	last   time.Time
+}
+
+// take removes a token from the bucket if one is available. Otherwise it
+// returns how long the caller has to wait for the next token.
+func (b *bucket) take(now time.Time, rate float64, burst int) (bool, time.Duration) {
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := (1 - b.tokens) / rate
+	return false, time.Duration(wait * float64(time.Second))
+}
+
+// full reports whether the bucket would be full at now, in which case it is
+// indistinguishable from a new one and can be dropped.
+func (b *bucket) full(now time.Time, rate float64, burst int) bool {
+	return b.tokens+now.Sub(b.last).Seconds()*rate >= float64(burst)
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Limit bounds how often a single caller may invoke a method and how many
+// calls of the method may run at once across all callers. Zero values mean
+// no limit.
+type Limit struct {
+	// Rate is the number of calls per second each caller may make.
+	Rate float64 `json:"rate"`
+	// Burst is the number of calls a caller may make at once after being
+	// idle. It defaults to Rate, rounded up.
+	Burst int `json:"burst"`
+	// MaxConcurrent is the number of calls of the method allowed in flight
+	// at once. For streaming methods this bounds the number of open streams.
+	MaxConcurrent int `json:"maxConcurrent"`
+}
+
+// Config holds the limits of every method. Methods are keyed by their full
+// name, e.g. "/greet.GreetService/GreetEveryone", and methods without an
+// entry use Default.
+//
+// Callers are told apart by the host of their peer address. TrustClientID
+// tells them apart by their x-client-id metadata instead, which callers can
+// set to anything, so it is only safe behind a proxy that sets or
+// authenticates it.
+type Config struct {
+	Default       Limit            `json:"default"`
+	Methods       map[string]Limit `json:"methods"`
+	TrustClientID bool             `json:"trustClientId"`
+}
+
+// LoadConfig reads a JSON config file.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading rate limit config: %v", err)
+	}
+	var c Config
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("parsing rate limit config: %v", err)
+	}
+	if err := c.Default.validate(); err != nil {
+		return nil, fmt.Errorf("default: %v", err)
+	}
+	for m, l := range c.Methods {
+		if err := l.validate(); err != nil {
+			return nil, fmt.Errorf("%v: %v", m, err)
+		}
+	}
+	return &c, nil
+}
+
+func (c *Config) limit(method string) Limit {
+	if l, ok := c.Methods[method]; ok {
+		return l
+	}
+	return c.Default
+}
+
+func (l Limit) validate() error {
+	if l.Rate < 0 || l.Burst < 0 || l.MaxConcurrent < 0 {
+		return fmt.Errorf("limits must not be negative")
+	}
+	return nil
+}
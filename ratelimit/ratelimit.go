@@ -0,0 +1,181 @@
+// Package ratelimit provides server interceptors that limit how often each
+// caller, as identified by the caller package, may invoke a method and how
+// many calls of a method may be in flight at once. Callers are told apart by
+// their peer host unless Config.TrustClientID is set.
+//
+// Rejected calls fail with codes.ResourceExhausted. The time after which the
+// caller may try again is attached both as a google.rpc.RetryInfo status
+// detail and as "retry-after-ms" trailer metadata.
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-grpc/caller"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// RetryAfterKey is the trailer metadata key carrying the number of
+	// milliseconds a rejected caller should wait before trying again.
+	RetryAfterKey = "retry-after-ms"
+
+	// sweepInterval is how often idle buckets are dropped.
+	sweepInterval = time.Minute
+	// maxBuckets bounds the number of buckets kept. Once reached, the bucket
+	// used longest ago is dropped to make room for a new one.
+	maxBuckets = 100000
+)
+
+type bucketKey struct {
+	caller string
+	method string
+}
+
+// Limiter enforces a Config. It is safe for concurrent use.
+type Limiter struct {
+	cfg *Config
+
+	mu sync.Mutex
+	// buckets indexes the elements of lru, which holds every *bucket with
+	// the one used last first.
+	buckets   map[bucketKey]*list.Element
+	lru       *list.List
+	inFlight  map[string]int
+	lastSweep time.Time
+}
+
+// New returns a Limiter enforcing cfg.
+func New(cfg *Config) *Limiter {
+	return &Limiter{
+		cfg:       cfg,
+		buckets:   make(map[bucketKey]*list.Element),
+		lru:       list.New(),
+		inFlight:  make(map[string]int),
+		lastSweep: time.Now(),
+	}
+}
+
+// UnaryInterceptor returns an interceptor applying the limits to unary RPCs.
+func (l *Limiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		release, err := l.acquire(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor returns an interceptor applying the limits to streaming
+// RPCs. A stream counts against MaxConcurrent for as long as it is open.
+func (l *Limiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, err := l.acquire(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		defer release()
+		return handler(srv, ss)
+	}
+}
+
+// acquire admits a call of method or returns the error to reject it with. The
+// returned function must be called once an admitted call has finished.
+func (l *Limiter) acquire(ctx context.Context, method string) (func(), error) {
+	lim := l.cfg.limit(method)
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	if lim.MaxConcurrent > 0 && l.inFlight[method] >= lim.MaxConcurrent {
+		// Concurrency slots free up as soon as any call finishes, so there is
+		// no meaningful wait to suggest.
+		return nil, reject(ctx, 0, "too many concurrent calls to %v", method)
+	}
+	if lim.Rate > 0 {
+		key := bucketKey{caller: caller.Host(ctx), method: method}
+		if l.cfg.TrustClientID {
+			key.caller = caller.ID(ctx)
+		}
+		var b *bucket
+		if e, ok := l.buckets[key]; ok {
+			b = e.Value.(*bucket)
+			l.lru.MoveToFront(e)
+		} else {
+			if len(l.buckets) >= maxBuckets {
+				l.remove(l.lru.Back())
+			}
+			b = &bucket{key: key, tokens: float64(burst(lim)), last: now}
+			l.buckets[key] = l.lru.PushFront(b)
+		}
+		if ok, wait := b.take(now, lim.Rate, burst(lim)); !ok {
+			return nil, reject(ctx, wait, "rate limit exceeded for %v by %v", method, key.caller)
+		}
+	}
+
+	l.inFlight[method]++
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.inFlight[method]--
+	}, nil
+}
+
+// sweep drops buckets that have refilled completely, at most once every
+// sweepInterval. l.mu must be held.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for e := l.lru.Front(); e != nil; {
+		next := e.Next()
+		b := e.Value.(*bucket)
+		lim := l.cfg.limit(b.key.method)
+		if b.full(now, lim.Rate, burst(lim)) {
+			l.remove(e)
+		}
+		e = next
+	}
+}
+
+// remove drops the bucket held by e. l.mu must be held.
+func (l *Limiter) remove(e *list.Element) {
+	b := l.lru.Remove(e).(*bucket)
+	delete(l.buckets, b.key)
+}
+
+func burst(lim Limit) int {
+	if lim.Burst > 0 {
+		return lim.Burst
+	}
+	return int(lim.Rate + 0.999)
+}
+
+func reject(ctx context.Context, wait time.Duration, format string, a ...interface{}) error {
+	ms := wait.Milliseconds()
+	if wait > 0 && ms == 0 {
+		ms = 1
+	}
+	grpc.SetTrailer(ctx, metadata.Pairs(RetryAfterKey, strconv.FormatInt(ms, 10)))
+
+	st := status.New(codes.ResourceExhausted, fmt.Sprintf(format, a...))
+	if d, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)}); err == nil {
+		st = d
+	}
+	return st.Err()
+}
@@ -0,0 +1,53 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+
+	"go-grpc/calculator/calculatorpb"
+	"go-grpc/caller"
+	"go-grpc/harness"
+	"go-grpc/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TestCallerKey checks that callers on the same peer share a bucket whatever
+// client ID they send, unless client IDs are trusted.
+func TestCallerKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		trust    bool
+		wantCode codes.Code
+	}{
+		{"peer", false, codes.ResourceExhausted},
+		{"trusted client ID", true, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// One call per caller, without refilling during the test.
+			cfg := &ratelimit.Config{
+				Default:       ratelimit.Limit{Rate: 0.001, Burst: 1},
+				TrustClientID: tt.trust,
+			}
+			l := ratelimit.New(cfg)
+			c := harness.StartCalculator(t, nil,
+				grpc.ChainUnaryInterceptor(l.UnaryInterceptor()),
+				grpc.ChainStreamInterceptor(l.StreamInterceptor()),
+			)
+			for i, id := range []string{"first", "second"} {
+				ctx := metadata.AppendToOutgoingContext(context.Background(), caller.IDKey, id)
+				_, err := c.Calculate(ctx, &calculatorpb.CalculatorRequest{X: 1, Y: 2})
+				want := codes.OK
+				if i > 0 {
+					want = tt.wantCode
+				}
+				if status.Code(err) != want {
+					t.Errorf("Calculate() as %v error = %v, want code %v", id, err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Package replay records the RPCs a server handles, with the order and
+// timing of the messages of their streams, and replays them against another
+// server, reporting where its responses differ from the recorded ones. This
+// lets changes to a server be regression tested against real traffic.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxStreamMessages bounds how many messages are recorded per call, so that
+// long-lived streams do not produce unbounded records.
+const maxStreamMessages = 1000
+
+// Recorder provides interceptors that append a Call to a Recording for every
+// RPC. Failing to write a call is logged rather than failing the RPC.
+type Recorder struct {
+	rec *Recording
+}
+
+// NewRecorder returns a Recorder writing to rec.
+func NewRecorder(rec *Recording) *Recorder {
+	return &Recorder{rec: rec}
+}
+
+// UnaryInterceptor returns an interceptor recording unary RPCs.
+func (r *Recorder) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		c := newCall(ctx, info.FullMethod)
+		c.add(false, req)
+		resp, err := handler(ctx, req)
+		if err == nil {
+			c.add(true, resp)
+		}
+		r.finish(c, err)
+		return resp, err
+	}
+}
+
+// StreamInterceptor returns an interceptor recording streaming RPCs,
+// including every message received and sent on the stream.
+func (r *Recorder) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		c := newCall(ss.Context(), info.FullMethod)
+		err := handler(srv, &serverStream{ServerStream: ss, call: c})
+		r.finish(c, err)
+		return err
+	}
+}
+
+type call struct {
+	mu    sync.Mutex
+	start time.Time
+	Call
+}
+
+func newCall(ctx context.Context, method string) *call {
+	now := time.Now()
+	c := &call{
+		start: now,
+		Call:  Call{Time: now, Method: method},
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for k, v := range md {
+		// Binary values do not survive being written as JSON strings.
+		if strings.HasPrefix(k, ":") || strings.HasPrefix(k, "grpc-") || strings.HasSuffix(k, "-bin") ||
+			k == "content-type" || k == "user-agent" {
+			continue
+		}
+		if c.Metadata == nil {
+			c.Metadata = make(map[string][]string)
+		}
+		c.Metadata[k] = v
+	}
+	if d, ok := ctx.Deadline(); ok {
+		c.Timeout = d.Sub(now)
+	}
+	return c
+}
+
+// add appends the JSON form of m to the messages, returning its index or -1
+// if it was not recorded.
+func (c *call) add(response bool, m interface{}) int {
+	pm, ok := m.(proto.Message)
+	if !ok {
+		return -1
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.Messages) >= maxStreamMessages {
+		c.Truncated = true
+		return -1
+	}
+	b, err := protojson.Marshal(pm)
+	if err != nil {
+		b, _ = json.Marshal(err.Error())
+	}
+	c.Messages = append(c.Messages, Message{Response: response, Offset: time.Since(c.start), Body: b})
+	return len(c.Messages) - 1
+}
+
+// remove drops the message at index i, which failed to be sent.
+func (c *call) remove(i int) {
+	if i < 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Messages = append(c.Messages[:i], c.Messages[i+1:]...)
+}
+
+func (r *Recorder) finish(c *call, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	st := status.Convert(err)
+	c.Code = st.Code().String()
+	c.Message = st.Message()
+	c.Duration = time.Since(c.start)
+	if err := r.rec.Append(&c.Call); err != nil {
+		log.Printf("Failed to record call: %v", err)
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	call *call
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.call.add(false, m)
+	}
+	return err
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	// The response is recorded before it is sent, as the client may answer
+	// it before SendMsg returns.
+	i := s.call.add(true, m)
+	err := s.ServerStream.SendMsg(m)
+	if err != nil {
+		s.call.remove(i)
+	}
+	return err
+}
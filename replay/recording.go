@@ -0,0 +1,101 @@
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// maxLineBytes bounds the size of a single call when reading a recording.
+const maxLineBytes = 64 << 20
+
+// Call is the record of one RPC. Messages are stored in their protobuf JSON
+// form.
+type Call struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	// Metadata is the metadata the client sent, leaving out the headers set
+	// by gRPC itself.
+	Metadata map[string][]string `json:"metadata,omitempty"`
+	// Timeout is the time the client gave the call to complete, zero if it
+	// set no deadline.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Messages are the messages of both directions in the order the server
+	// saw them.
+	Messages []Message `json:"messages,omitempty"`
+	// Truncated is set if the call carried more messages than were
+	// recorded.
+	Truncated bool          `json:"truncated,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	Code      string        `json:"code"`
+	Message   string        `json:"message,omitempty"`
+}
+
+// Message is one message of a Call.
+type Message struct {
+	// Response is set for the messages sent by the server.
+	Response bool `json:"response,omitempty"`
+	// Offset is the time since the start of the call.
+	Offset time.Duration   `json:"offset"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// Recording is a JSON lines file holding one Call per line.
+type Recording struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// Open opens the recording at path for appending, creating it if needed.
+func Open(path string) (*Recording, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &Recording{f: f}, nil
+}
+
+// Append writes c to the recording.
+func (r *Recording) Append(c *Call) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.f.Write(b)
+	return err
+}
+
+// Close closes the recording file.
+func (r *Recording) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
+
+// ReadFile reads the calls of the recording at path, in the order they
+// ended.
+func ReadFile(path string) ([]*Call, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var calls []*Call
+	s := bufio.NewScanner(f)
+	s.Buffer(nil, maxLineBytes)
+	for line := 1; s.Scan(); line++ {
+		var c Call
+		if err := json.Unmarshal(s.Bytes(), &c); err != nil {
+			return nil, fmt.Errorf("%v:%d: %v", path, line, err)
+		}
+		calls = append(calls, &c)
+	}
+	return calls, s.Err()
+}
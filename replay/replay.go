@@ -0,0 +1,237 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Options configures how calls are replayed.
+type Options struct {
+	// Timing keeps the recorded time between the requests of a stream
+	// rather than sending them as fast as the responses allow.
+	Timing bool
+	// Timeout bounds the time a call may take if it was recorded without a
+	// deadline, 0 for no bound.
+	Timeout time.Duration
+	// Ignore lists fields left out of the comparison of responses, by their
+	// name in the .proto file, e.g. "resume_token". Fields of that name are
+	// ignored in every message, however deeply nested.
+	Ignore []string
+}
+
+// Result is the outcome of replaying a Call.
+type Result struct {
+	Call *Call
+	// Code is the status code the replayed call ended with.
+	Code string
+	// Diffs describe how the replayed call differed from the recorded one.
+	Diffs []string
+}
+
+// OK reports whether the replayed call matched the recorded one.
+func (r *Result) OK() bool {
+	return len(r.Diffs) == 0
+}
+
+func (r *Result) diff(format string, a ...interface{}) {
+	r.Diffs = append(r.Diffs, fmt.Sprintf(format, a...))
+}
+
+// Replay sends the requests of c over cc, in their recorded order, and
+// compares the responses and status received with the recorded ones. It
+// fails only if c cannot be replayed at all, e.g. if its method is unknown.
+//
+// The message types of the method are looked up in the global registry, so
+// the package generated from its .proto file must be linked in.
+func Replay(ctx context.Context, cc grpc.ClientConnInterface, c *Call, opts Options) (*Result, error) {
+	md, err := findMethod(c.Method)
+	if err != nil {
+		return nil, err
+	}
+	input, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", c.Method, err)
+	}
+	output, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", c.Method, err)
+	}
+	ignore := make(map[protoreflect.Name]bool)
+	for _, f := range opts.Ignore {
+		ignore[protoreflect.Name(f)] = true
+	}
+
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = opts.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(ctx, metadata.MD(c.Metadata).Copy()))
+	defer cancel()
+
+	desc := &grpc.StreamDesc{
+		StreamName:    string(md.Name()),
+		ServerStreams: md.IsStreamingServer(),
+		ClientStreams: md.IsStreamingClient(),
+	}
+	stream, err := cc.NewStream(ctx, desc, c.Method)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", c.Method, err)
+	}
+
+	r := &Result{Call: c}
+	lastRequest := -1
+	for i, m := range c.Messages {
+		if !m.Response {
+			lastRequest = i
+		}
+	}
+	start := time.Now()
+	closed := false
+	responses := 0
+	var end error
+	for i, m := range c.Messages {
+		if !m.Response {
+			if opts.Timing {
+				wait(ctx, time.Until(start.Add(m.Offset)))
+			}
+			req := input.New().Interface()
+			if err := protojson.Unmarshal(m.Body, req); err != nil {
+				return nil, fmt.Errorf("%v: request %d: %v", c.Method, i, err)
+			}
+			if err := stream.SendMsg(req); err != nil {
+				// The server ended the call, whose status is received below.
+				break
+			}
+			continue
+		}
+
+		if i > lastRequest && !closed {
+			// The server may be waiting for the client to finish.
+			stream.CloseSend()
+			closed = true
+		}
+		want := output.New().Interface()
+		if err := protojson.Unmarshal(m.Body, want); err != nil {
+			return nil, fmt.Errorf("%v: response %d: %v", c.Method, responses, err)
+		}
+		clearFields(want.ProtoReflect(), ignore)
+		got := output.New().Interface()
+		if end = stream.RecvMsg(got); end != nil {
+			r.diff("response %d: got none, want %s", responses, format(want))
+			break
+		}
+		clearFields(got.ProtoReflect(), ignore)
+		if !proto.Equal(got, want) {
+			r.diff("response %d: got %s, want %s", responses, format(got), format(want))
+		}
+		responses++
+	}
+	if !closed {
+		stream.CloseSend()
+	}
+
+	for end == nil {
+		got := output.New().Interface()
+		if end = stream.RecvMsg(got); end == nil && !c.Truncated {
+			r.diff("response %d: got %s, want none", responses, format(got))
+			responses++
+		}
+	}
+	if end == io.EOF {
+		end = nil
+	}
+	st := status.Convert(end)
+	r.Code = st.Code().String()
+	if r.Code != c.Code {
+		r.diff("status: got %v (%v), want %v (%v)", r.Code, st.Message(), c.Code, c.Message)
+	}
+	return r, nil
+}
+
+// findMethod looks up the descriptor of a method by its full name, e.g.
+// "/greet.GreetService/Greet".
+func findMethod(name string) (protoreflect.MethodDescriptor, error) {
+	i := strings.LastIndex(name, "/")
+	if i <= 0 {
+		return nil, fmt.Errorf("invalid method name %q", name)
+	}
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(strings.TrimPrefix(name[:i], "/")))
+	if err != nil {
+		return nil, fmt.Errorf("%v: unknown service: %v", name, err)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%v: %v is not a service", name, d.FullName())
+	}
+	md := sd.Methods().ByName(protoreflect.Name(name[i+1:]))
+	if md == nil {
+		return nil, fmt.Errorf("%v: unknown method", name)
+	}
+	return md, nil
+}
+
+// clearFields clears the fields of m named in ignore, in m and every message
+// nested in it.
+func clearFields(m protoreflect.Message, ignore map[protoreflect.Name]bool) {
+	if len(ignore) == 0 {
+		return
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if ignore[fd.Name()] {
+			m.Clear(fd)
+			return true
+		}
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			l := v.List()
+			for i := 0; i < l.Len(); i++ {
+				clearFields(l.Get(i).Message(), ignore)
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				clearFields(v.Message(), ignore)
+				return true
+			})
+		case fd.Message() != nil && !fd.IsMap():
+			clearFields(v.Message(), ignore)
+		}
+		return true
+	})
+}
+
+func format(m proto.Message) string {
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
+// wait sleeps for d, or until ctx is done.
+func wait(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go-grpc/replay"
+	"google.golang.org/grpc"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	// The message types of the recorded methods are looked up in the
+	// registry the generated packages add them to.
+	_ "go-grpc/calculator/calculatorpb"
+	_ "go-grpc/greet/greetpb"
+)
+
+var (
+	addr         = flag.String("addr", "localhost:50051", "address of the server to replay the calls against")
+	recording    = flag.String("recording", "", "path of the recording made by a server's -record flag")
+	method       = flag.String("method", "", "replay only the calls of methods containing this string, e.g. GreetEveryone")
+	timing       = flag.Bool("timing", false, "keep the recorded time between the requests of a stream")
+	callTimeout  = flag.Duration("call_timeout", 10*time.Second, "time given to calls recorded without a deadline, 0 for no limit")
+	ignoreFields = flag.String("ignore_fields", "time,peer,resume_token", "comma-separated names of response fields not compared, such as ones holding times")
+	verbose      = flag.Bool("v", false, "also print the calls that matched")
+)
+
+func main() {
+	flag.Parse()
+	if *recording == "" {
+		log.Fatalf("No -recording given")
+	}
+
+	calls, err := replay.ReadFile(*recording)
+	if err != nil {
+		log.Fatalf("Could not read recording: %v", err)
+	}
+
+	cc, err := grpc.Dial(*addr, grpc.WithInsecure())
+	if err != nil {
+		log.Fatalf("Could not connect: %v", err)
+	}
+	defer cc.Close()
+
+	opts := replay.Options{
+		Timing:  *timing,
+		Timeout: *callTimeout,
+	}
+	if *ignoreFields != "" {
+		opts.Ignore = strings.Split(*ignoreFields, ",")
+	}
+
+	var replayed, differed, skipped int
+	for i, c := range calls {
+		if !strings.Contains(c.Method, *method) {
+			continue
+		}
+		r, err := replay.Replay(context.Background(), cc, c, opts)
+		if err != nil {
+			// E.g. calls of services this tool does not link in.
+			skipped++
+			fmt.Printf("SKIP  %d %v\n", i+1, err)
+			continue
+		}
+		replayed++
+		if r.OK() {
+			if *verbose {
+				fmt.Printf("ok    %d %v %v\n", i+1, c.Method, r.Code)
+			}
+			continue
+		}
+		differed++
+		fmt.Printf("DIFF  %d %v recorded %v\n", i+1, c.Method, c.Time.Format(time.RFC3339Nano))
+		for _, d := range r.Diffs {
+			fmt.Printf("      %v\n", d)
+		}
+	}
+	fmt.Printf("%d calls replayed, %d matched, %d differed, %d skipped\n", replayed, replayed-differed, differed, skipped)
+	if differed > 0 {
+		os.Exit(1)
+	}
+}
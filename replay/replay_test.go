@@ -0,0 +1,185 @@
+package replay_test
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-grpc/greet/greetfake"
+	"go-grpc/greet/greetpb"
+	"go-grpc/greet/greetserver"
+	"go-grpc/harness"
+	"go-grpc/replay"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// newServer returns a greet server whose time of day is always afternoon,
+// so that its greetings do not change between recording and replaying.
+func newServer(t *testing.T) *greetserver.Server {
+	t.Helper()
+	catalog := harness.Catalog(t)
+	catalog.Now = func() time.Time {
+		return time.Date(2022, 2, 8, 14, 0, 0, 0, time.UTC)
+	}
+	return harness.NewGreetServer(t, greetserver.Options{Greetings: catalog})
+}
+
+// record makes calls of every shape to a greet server recording them, and
+// returns the recorded calls.
+func record(t *testing.T) []*replay.Call {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "recording.jsonl")
+	rec, err := replay.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	r := replay.NewRecorder(rec)
+	c := harness.StartGreet(t, newServer(t),
+		grpc.ChainUnaryInterceptor(r.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(r.StreamInterceptor()),
+	)
+	ann := &greetpb.Greeting{FirstName: "Ann"}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-client-id", "recorder")
+
+	if _, err := c.Greet(ctx, &greetpb.GreetRequest{Greeting: ann}); err != nil {
+		t.Fatalf("Greet() error = %v", err)
+	}
+	// An error is recorded too.
+	c.GreetManyTimes(ctx, &greetpb.GreetManyTimesRequest{Greeting: ann, Count: -1})
+	many, err := c.GreetManyTimes(ctx, &greetpb.GreetManyTimesRequest{Greeting: ann, Count: 3, Interval: durationpb.New(time.Millisecond)})
+	if err != nil {
+		t.Fatalf("GreetManyTimes() error = %v", err)
+	}
+	for {
+		if _, err := many.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+	}
+	long, err := c.LongGreet(ctx)
+	if err != nil {
+		t.Fatalf("LongGreet() error = %v", err)
+	}
+	for _, name := range []string{"Ann", "Bob"} {
+		long.Send(&greetpb.LongGreetRequest{Greeting: &greetpb.Greeting{FirstName: name}})
+	}
+	if _, err := long.CloseAndRecv(); err != nil {
+		t.Fatalf("LongGreet() error = %v", err)
+	}
+	everyone, err := c.GreetEveryone(ctx)
+	if err != nil {
+		t.Fatalf("GreetEveryone() error = %v", err)
+	}
+	for _, name := range []string{"Ann", "Bob"} {
+		everyone.Send(&greetpb.GreetEveryoneRequest{Greeting: &greetpb.Greeting{FirstName: name}})
+		if _, err := everyone.Recv(); err != nil {
+			t.Fatalf("GreetEveryone() error = %v", err)
+		}
+	}
+	everyone.CloseSend()
+	if _, err := everyone.Recv(); err != io.EOF {
+		t.Fatalf("GreetEveryone() error = %v, want EOF", err)
+	}
+
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	calls, err := replay.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	return calls
+}
+
+func TestRecord(t *testing.T) {
+	calls := record(t)
+	want := []struct {
+		method   string
+		messages string
+		code     string
+	}{
+		{"/greet.GreetService/Greet", "qr", "OK"},
+		{"/greet.GreetService/GreetManyTimes", "q", "InvalidArgument"},
+		{"/greet.GreetService/GreetManyTimes", "qrrr", "OK"},
+		{"/greet.GreetService/LongGreet", "qqr", "OK"},
+		{"/greet.GreetService/GreetEveryone", "qrqr", "OK"},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("recorded %d calls, want %d", len(calls), len(want))
+	}
+	for i, w := range want {
+		c := calls[i]
+		var messages strings.Builder
+		for _, m := range c.Messages {
+			if m.Response {
+				messages.WriteByte('r')
+			} else {
+				messages.WriteByte('q')
+			}
+		}
+		if c.Method != w.method || messages.String() != w.messages || c.Code != w.code {
+			t.Errorf("call %d = %v %v %v, want %v %v %v", i, c.Method, messages.String(), c.Code, w.method, w.messages, w.code)
+		}
+		if got := c.Metadata["x-client-id"]; len(got) != 1 || got[0] != "recorder" {
+			t.Errorf("call %d metadata x-client-id = %q, want [recorder]", i, got)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	calls := record(t)
+	srv := newServer(t)
+	cc := harness.Start(t, func(s *grpc.Server) {
+		greetpb.RegisterGreetServiceServer(s, srv)
+	})
+	for _, c := range calls {
+		r, err := replay.Replay(context.Background(), cc, c, replay.Options{Timing: true, Timeout: 5 * time.Second})
+		if err != nil {
+			t.Fatalf("Replay(%v) error = %v", c.Method, err)
+		}
+		if !r.OK() {
+			t.Errorf("Replay(%v) diffs = %q, want none", c.Method, r.Diffs)
+		}
+	}
+}
+
+func TestReplayDiffs(t *testing.T) {
+	calls := record(t)
+	s := greetfake.New()
+	s.On("Greet").Return(&greetpb.GreetResponse{Result: "Howdy"})
+	s.On("GreetManyTimes").Return(&greetpb.GreetManyTimesResponse{Result: "Howdy"})
+	s.On("LongGreet").Return(&greetpb.LongGreetResponse{Result: "Howdy"})
+	cc := harness.Start(t, func(srv *grpc.Server) {
+		greetpb.RegisterGreetServiceServer(srv, s)
+	})
+	tests := []struct {
+		call int
+		want []string
+	}{
+		{0, []string{`response 0: got {"result":"Howdy"}, want {"result":"Good`}},
+		{1, []string{`response 0: got {"result":"Howdy"}, want none`, "status: got OK"}},
+		{2, []string{`response 0: got {"result":"Howdy"}`, "response 1: got none"}},
+		{4, []string{"response 0: got none", "status: got Unimplemented"}},
+	}
+	for _, tt := range tests {
+		c := calls[tt.call]
+		r, err := replay.Replay(context.Background(), cc, c, replay.Options{Ignore: []string{"sequence", "resume_token"}})
+		if err != nil {
+			t.Fatalf("Replay(%v) error = %v", c.Method, err)
+		}
+		if len(r.Diffs) != len(tt.want) {
+			t.Fatalf("Replay(%v) diffs = %q, want %d", c.Method, r.Diffs, len(tt.want))
+		}
+		for i, want := range tt.want {
+			if !strings.HasPrefix(r.Diffs[i], want) {
+				t.Errorf("Replay(%v) diff %d = %q, want prefix %q", c.Method, i, r.Diffs[i], want)
+			}
+		}
+	}
+}
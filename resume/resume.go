@@ -0,0 +1,67 @@
+// Package resume lets server-streaming RPCs continue where a dropped stream
+// stopped.
+//
+// Servers number the responses of a stream from 1 and attach to each one a
+// token naming its position. A client that loses the stream calls the RPC
+// again with the same request and the token of the last response it got, and
+// the server skips the responses the client already has. Tokens are bound to
+// the request they were issued for, so they cannot resume a different one.
+//
+// Request messages carry the token in a string field named resume_token and
+// response messages in a field of the same name.
+package resume
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// TokenField is the name of the field holding resume tokens.
+const TokenField = "resume_token"
+
+// ErrInvalidToken is returned by Position for tokens that were not issued
+// for the request.
+var ErrInvalidToken = errors.New("invalid resume token")
+
+// Token returns the token resuming the stream opened by req after the
+// response numbered seq.
+func Token(req proto.Message, seq uint64) string {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b, fingerprint(req))
+	binary.BigEndian.PutUint64(b[8:], seq)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Position returns the sequence number of the last response the client of
+// req already received, 0 if req carries no token.
+func Position(req proto.Message) (uint64, error) {
+	m := req.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName(TokenField)
+	if fd == nil || m.Get(fd).String() == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(m.Get(fd).String())
+	if err != nil || len(b) != 16 {
+		return 0, ErrInvalidToken
+	}
+	if binary.BigEndian.Uint64(b) != fingerprint(req) {
+		return 0, ErrInvalidToken
+	}
+	return binary.BigEndian.Uint64(b[8:]), nil
+}
+
+// fingerprint hashes req without its resume token.
+func fingerprint(req proto.Message) uint64 {
+	m := proto.Clone(req).ProtoReflect()
+	if fd := m.Descriptor().Fields().ByName(TokenField); fd != nil {
+		m.Clear(fd)
+	}
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(m.Interface())
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
@@ -0,0 +1,92 @@
+package resume
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Message is a response of a resumable stream.
+type Message interface {
+	proto.Message
+	GetResumeToken() string
+}
+
+// RecvFunc receives the next response of a stream.
+type RecvFunc func() (Message, error)
+
+// OpenFunc calls a server-streaming RPC, passing token as the resume token of
+// the request, and returns the function receiving its responses.
+type OpenFunc func(ctx context.Context, token string) (RecvFunc, error)
+
+// Options control how a Stream reconnects.
+type Options struct {
+	// MaxAttempts is the number of times in a row the stream is reopened
+	// without receiving anything before giving up. 5 if zero.
+	MaxAttempts int
+	// Backoff is the wait before the first reconnection, doubled for each
+	// further attempt. 100ms if zero.
+	Backoff time.Duration
+}
+
+// Stream receives the responses of a server-streaming RPC, transparently
+// reopening it from the last received response when it fails with
+// Unavailable, which is how gRPC reports dropped connections.
+type Stream struct {
+	ctx   context.Context
+	open  OpenFunc
+	opts  Options
+	recv  RecvFunc
+	token string
+}
+
+// NewStream returns a stream calling open to start the RPC. The RPC is only
+// started by the first call to Recv.
+func NewStream(ctx context.Context, open OpenFunc, opts Options) *Stream {
+	if opts.MaxAttempts == 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.Backoff == 0 {
+		opts.Backoff = 100 * time.Millisecond
+	}
+	return &Stream{ctx: ctx, open: open, opts: opts}
+}
+
+// Recv returns the next response, or io.EOF once the stream is done.
+func (s *Stream) Recv() (Message, error) {
+	backoff := s.opts.Backoff
+	for attempt := 1; ; attempt++ {
+		msg, err := s.next()
+		if err == nil {
+			s.token = msg.GetResumeToken()
+			return msg, nil
+		}
+		if err == io.EOF || status.Code(err) != codes.Unavailable || attempt == s.opts.MaxAttempts {
+			return nil, err
+		}
+
+		s.recv = nil
+		select {
+		case <-s.ctx.Done():
+			return nil, status.FromContextError(s.ctx.Err()).Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// next receives the next response, opening the stream first if needed.
+func (s *Stream) next() (Message, error) {
+	if s.recv == nil {
+		recv, err := s.open(s.ctx, s.token)
+		if err != nil {
+			return nil, err
+		}
+		s.recv = recv
+	}
+	return s.recv()
+}
@@ -0,0 +1,60 @@
+// Package rpcerror formats gRPC status errors, including the google.rpc
+// error details attached to them, for display by the command line clients.
+package rpcerror
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// Reason returns the reason and domain of the ErrorInfo detail attached to
+// err, if any.
+func Reason(err error) (reason, domain string, ok bool) {
+	for _, d := range status.Convert(err).Details() {
+		if info, isInfo := d.(*errdetails.ErrorInfo); isInfo {
+			return info.GetReason(), info.GetDomain(), true
+		}
+	}
+	return "", "", false
+}
+
+// Describe returns a human-readable, possibly multi-line description of err
+// listing its status code, message and details.
+func Describe(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err.Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v: %v", st.Code(), st.Message())
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			fmt.Fprintf(&b, "\n  reason %v (%v)", d.GetReason(), d.GetDomain())
+			keys := make([]string, 0, len(d.GetMetadata()))
+			for k := range d.GetMetadata() {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fmt.Fprintf(&b, "\n    %v: %v", k, d.GetMetadata()[k])
+			}
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				fmt.Fprintf(&b, "\n  field %v: %v", v.GetField(), v.GetDescription())
+			}
+		case *errdetails.RetryInfo:
+			fmt.Fprintf(&b, "\n  retry after %v", d.GetRetryDelay().AsDuration())
+		case error:
+			fmt.Fprintf(&b, "\n  undecodable detail: %v", d)
+		default:
+			fmt.Fprintf(&b, "\n  %v", d)
+		}
+	}
+	return b.String()
+}
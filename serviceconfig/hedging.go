@@ -0,0 +1,96 @@
+package serviceconfig
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type attemptResult struct {
+	reply proto.Message
+	err   error
+}
+
+// hedgingInterceptor sends up to MaxAttempts copies of a unary RPC, each
+// HedgingDelay after the previous one, and commits to the first response that
+// is either a success or carries a fatal status code. Outstanding attempts are
+// cancelled once a response is committed.
+func (c *Config) hedgingInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	hp := c.hedgingPolicy(method)
+	out, ok := reply.(proto.Message)
+	if hp == nil || !ok {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan attemptResult, hp.MaxAttempts)
+	var wg sync.WaitGroup
+	send := func() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := proto.Clone(out)
+			proto.Reset(r)
+			err := invoker(ctx, method, req, r, cc, opts...)
+			results <- attemptResult{reply: r, err: err}
+		}()
+	}
+
+	send()
+	sent, pending := 1, 1
+	timer := time.NewTimer(hp.HedgingDelay)
+	defer timer.Stop()
+
+	var last error
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			if sent < hp.MaxAttempts && c.throttle.allow() {
+				send()
+				sent++
+				pending++
+				timer.Reset(hp.HedgingDelay)
+			}
+		case res := <-results:
+			pending--
+			c.throttle.record(res.err)
+			if res.err == nil {
+				cancel()
+				wg.Wait()
+				proto.Merge(out, res.reply)
+				return nil
+			}
+			last = res.err
+			if !hp.nonFatal(status.Code(res.err)) {
+				cancel()
+				wg.Wait()
+				return res.err
+			}
+			// A non-fatal failure sends the next hedge straight away rather
+			// than waiting out the rest of the delay.
+			if sent < hp.MaxAttempts && c.throttle.allow() {
+				send()
+				sent++
+				pending++
+				timer.Reset(hp.HedgingDelay)
+			}
+		}
+	}
+	return last
+}
+
+func (hp *HedgingPolicy) nonFatal(code codes.Code) bool {
+	for _, c := range hp.NonFatalStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
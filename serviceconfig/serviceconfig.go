@@ -0,0 +1,247 @@
+// Package serviceconfig loads gRPC service configs from JSON files for the
+// greet and calculator clients.
+//
+// Retry policies and retry throttling are handed to grpc-go unchanged, which
+// implements them natively. grpc-go does not implement hedging, so any
+// hedgingPolicy found in a methodConfig is applied by a client interceptor
+// from this package instead.
+package serviceconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// maxAttempts is the upper bound grpc-go places on retry and hedging attempts.
+const maxAttempts = 5
+
+// Config is a parsed service config ready to be applied to a client
+// connection.
+type Config struct {
+	raw      string
+	hedging  map[string]*HedgingPolicy
+	throttle *throttler
+}
+
+// HedgingPolicy describes how many copies of an RPC may be in flight at once
+// and how far apart they are sent.
+type HedgingPolicy struct {
+	MaxAttempts         int
+	HedgingDelay        time.Duration
+	NonFatalStatusCodes []codes.Code
+}
+
+type jsonConfig struct {
+	MethodConfig    []jsonMethodConfig   `json:"methodConfig"`
+	RetryThrottling *jsonRetryThrottling `json:"retryThrottling"`
+}
+
+type jsonMethodConfig struct {
+	Name          []jsonName         `json:"name"`
+	RetryPolicy   *jsonRetryPolicy   `json:"retryPolicy"`
+	HedgingPolicy *jsonHedgingPolicy `json:"hedgingPolicy"`
+}
+
+type jsonName struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+}
+
+type jsonRetryPolicy struct {
+	MaxAttempts          int          `json:"maxAttempts"`
+	InitialBackoff       string       `json:"initialBackoff"`
+	MaxBackoff           string       `json:"maxBackoff"`
+	BackoffMultiplier    float64      `json:"backoffMultiplier"`
+	RetryableStatusCodes []codes.Code `json:"retryableStatusCodes"`
+}
+
+type jsonHedgingPolicy struct {
+	MaxAttempts         int          `json:"maxAttempts"`
+	HedgingDelay        string       `json:"hedgingDelay"`
+	NonFatalStatusCodes []codes.Code `json:"nonFatalStatusCodes"`
+}
+
+type jsonRetryThrottling struct {
+	MaxTokens  float64 `json:"maxTokens"`
+	TokenRatio float64 `json:"tokenRatio"`
+}
+
+// Load reads and validates the service config stored at path.
+func Load(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading service config: %v", err)
+	}
+	return Parse(string(b))
+}
+
+// Parse validates a JSON service config. Unlike grpc-go, which only logs a
+// warning and drops a malformed retry policy, Parse rejects it so that a typo
+// in the file does not silently disable retries.
+func Parse(js string) (*Config, error) {
+	var jc jsonConfig
+	if err := json.Unmarshal([]byte(js), &jc); err != nil {
+		return nil, fmt.Errorf("parsing service config: %v", err)
+	}
+
+	c := &Config{
+		raw:     js,
+		hedging: make(map[string]*HedgingPolicy),
+	}
+	for i, mc := range jc.MethodConfig {
+		if mc.RetryPolicy != nil && mc.HedgingPolicy != nil {
+			return nil, fmt.Errorf("methodConfig[%d]: retryPolicy and hedgingPolicy are mutually exclusive", i)
+		}
+		if mc.RetryPolicy != nil {
+			if err := validateRetryPolicy(mc.RetryPolicy); err != nil {
+				return nil, fmt.Errorf("methodConfig[%d]: %v", i, err)
+			}
+		}
+		if mc.HedgingPolicy == nil {
+			continue
+		}
+		hp, err := convertHedgingPolicy(mc.HedgingPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("methodConfig[%d]: %v", i, err)
+		}
+		for _, n := range mc.Name {
+			path, err := n.path()
+			if err != nil {
+				return nil, fmt.Errorf("methodConfig[%d]: %v", i, err)
+			}
+			c.hedging[path] = hp
+		}
+	}
+
+	if rt := jc.RetryThrottling; rt != nil {
+		if rt.MaxTokens <= 0 || rt.MaxTokens > 1000 {
+			return nil, fmt.Errorf("retryThrottling: maxTokens (%v) out of range (0, 1000]", rt.MaxTokens)
+		}
+		if rt.TokenRatio <= 0 {
+			return nil, fmt.Errorf("retryThrottling: tokenRatio (%v) must be positive", rt.TokenRatio)
+		}
+		c.throttle = newThrottler(rt.MaxTokens, rt.TokenRatio)
+	}
+	return c, nil
+}
+
+// SetLoadBalancing selects the load balancing policy used by the channel. If
+// healthCheckService is not nil, client side health checking is enabled for
+// every backend against that service name, where "" checks the server as a
+// whole.
+func (c *Config) SetLoadBalancing(policy string, healthCheckService *string) error {
+	var sc map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(c.raw), &sc); err != nil {
+		return err
+	}
+	if sc == nil {
+		sc = make(map[string]json.RawMessage)
+	}
+	delete(sc, "loadBalancingPolicy")
+	lb, err := json.Marshal([]map[string]struct{}{{policy: {}}})
+	if err != nil {
+		return err
+	}
+	sc["loadBalancingConfig"] = lb
+	if healthCheckService != nil {
+		hc, err := json.Marshal(map[string]string{"serviceName": *healthCheckService})
+		if err != nil {
+			return err
+		}
+		sc["healthCheckConfig"] = hc
+	}
+	b, err := json.Marshal(sc)
+	if err != nil {
+		return err
+	}
+	c.raw = string(b)
+	return nil
+}
+
+// DialOptions returns the options that apply c to a client connection.
+func (c *Config) DialOptions() []grpc.DialOption {
+	opts := []grpc.DialOption{grpc.WithDefaultServiceConfig(c.raw)}
+	if len(c.hedging) > 0 {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(c.hedgingInterceptor))
+	}
+	return opts
+}
+
+// hedgingPolicy returns the policy for fullMethod, falling back to the
+// service-wide policy if the method has none of its own.
+func (c *Config) hedgingPolicy(fullMethod string) *HedgingPolicy {
+	if hp, ok := c.hedging[fullMethod]; ok {
+		return hp
+	}
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return c.hedging[fullMethod[:i+1]]
+	}
+	return nil
+}
+
+func (n jsonName) path() (string, error) {
+	if n.Service == "" {
+		return "", fmt.Errorf("hedgingPolicy requires a service name")
+	}
+	return "/" + n.Service + "/" + n.Method, nil
+}
+
+func validateRetryPolicy(rp *jsonRetryPolicy) error {
+	if rp.MaxAttempts < 2 {
+		return fmt.Errorf("retryPolicy: maxAttempts (%v) must be at least 2", rp.MaxAttempts)
+	}
+	ib, err := parseDuration(rp.InitialBackoff)
+	if err != nil || ib <= 0 {
+		return fmt.Errorf("retryPolicy: invalid initialBackoff %q", rp.InitialBackoff)
+	}
+	mb, err := parseDuration(rp.MaxBackoff)
+	if err != nil || mb <= 0 {
+		return fmt.Errorf("retryPolicy: invalid maxBackoff %q", rp.MaxBackoff)
+	}
+	if rp.BackoffMultiplier <= 0 {
+		return fmt.Errorf("retryPolicy: backoffMultiplier (%v) must be positive", rp.BackoffMultiplier)
+	}
+	if len(rp.RetryableStatusCodes) == 0 {
+		return fmt.Errorf("retryPolicy: retryableStatusCodes must not be empty")
+	}
+	return nil
+}
+
+func convertHedgingPolicy(jp *jsonHedgingPolicy) (*HedgingPolicy, error) {
+	if jp.MaxAttempts < 2 {
+		return nil, fmt.Errorf("hedgingPolicy: maxAttempts (%v) must be at least 2", jp.MaxAttempts)
+	}
+	hp := &HedgingPolicy{
+		MaxAttempts:         jp.MaxAttempts,
+		NonFatalStatusCodes: jp.NonFatalStatusCodes,
+	}
+	if hp.MaxAttempts > maxAttempts {
+		hp.MaxAttempts = maxAttempts
+	}
+	if jp.HedgingDelay != "" {
+		d, err := parseDuration(jp.HedgingDelay)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("hedgingPolicy: invalid hedgingDelay %q", jp.HedgingDelay)
+		}
+		hp.HedgingDelay = d
+	}
+	return hp, nil
+}
+
+// parseDuration parses durations in the protobuf JSON form used by service
+// configs, e.g. "0.1s" or "5s".
+func parseDuration(s string) (time.Duration, error) {
+	if len(s) < 2 || !strings.HasSuffix(s, "s") {
+		return 0, fmt.Errorf("malformed duration %q", s)
+	}
+	if c := s[len(s)-2]; (c < '0' || c > '9') && c != '.' {
+		return 0, fmt.Errorf("malformed duration %q", s)
+	}
+	return time.ParseDuration(s)
+}
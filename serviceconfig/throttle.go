@@ -0,0 +1,63 @@
+package serviceconfig
+
+import (
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// throttler implements the retry throttling budget from gRPC proposal A6 for
+// hedged attempts. grpc-go keeps its own budget for retries.
+//
+// The token count starts at maxTokens. Every failed attempt removes one token
+// and every successful attempt adds tokenRatio. Hedged attempts are only sent
+// while more than half of the tokens remain.
+type throttler struct {
+	mu         sync.Mutex
+	tokens     float64
+	maxTokens  float64
+	tokenRatio float64
+}
+
+func newThrottler(maxTokens, tokenRatio float64) *throttler {
+	return &throttler{
+		tokens:     maxTokens,
+		maxTokens:  maxTokens,
+		tokenRatio: tokenRatio,
+	}
+}
+
+// allow reports whether another hedged attempt may be sent. A nil throttler
+// allows everything.
+func (t *throttler) allow() bool {
+	if t == nil {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tokens > t.maxTokens/2
+}
+
+// record updates the token count with the outcome of one attempt.
+func (t *throttler) record(err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch status.Code(err) {
+	case codes.OK:
+		t.tokens += t.tokenRatio
+		if t.tokens > t.maxTokens {
+			t.tokens = t.maxTokens
+		}
+	case codes.Canceled:
+		// Attempts we cancelled ourselves say nothing about server health.
+	default:
+		t.tokens--
+		if t.tokens < 0 {
+			t.tokens = 0
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/proto" // registers the proto codec
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// invalidUTF8Field is the number of the unknown field Codec adds to a
+// message for every string field that was not valid UTF-8, holding the path
+// of the field. It is the largest field number, which messages are unlikely
+// to use.
+const invalidUTF8Field = protowire.MaxValidNumber
+
+// Codec is the proto codec of grpc, except that it decodes messages whose
+// string fields are not valid UTF-8 instead of failing. grpc reports
+// decoding failures as Internal errors before any interceptor runs, so the
+// codec replaces the invalid bytes by '?' and marks the fields, which
+// Message then reports as violations. Servers use it with
+//
+//	grpc.ForceServerCodec(validate.Codec{})
+type Codec struct{}
+
+var protoCodec = encoding.GetCodec("proto")
+
+func (Codec) Name() string {
+	return protoCodec.Name()
+}
+
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+	return protoCodec.Marshal(v)
+}
+
+func (Codec) Unmarshal(data []byte, v interface{}) error {
+	err := protoCodec.Unmarshal(data, v)
+	m, ok := v.(proto.Message)
+	if err == nil || !ok {
+		return err
+	}
+	clean := append([]byte(nil), data...)
+	var paths []string
+	sanitize(clean, m.ProtoReflect().Descriptor(), "", &paths)
+	if len(paths) == 0 {
+		return err
+	}
+	if err := protoCodec.Unmarshal(clean, v); err != nil {
+		return err
+	}
+	r := m.ProtoReflect()
+	unknown := r.GetUnknown()
+	for _, p := range paths {
+		unknown = protowire.AppendTag(unknown, invalidUTF8Field, protowire.BytesType)
+		unknown = protowire.AppendString(unknown, p)
+	}
+	r.SetUnknown(unknown)
+	return nil
+}
+
+// sanitize replaces the bytes that are not valid UTF-8 in the string fields
+// of b, the encoding of a message described by md, keeping their length.
+// The paths of the fields replaced, prefixed with prefix, are appended to
+// paths.
+func sanitize(b []byte, md protoreflect.MessageDescriptor, prefix string, paths *[]string) {
+	counts := make(map[protowire.Number]int)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, b); n < 0 {
+				return
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return
+		}
+		b = b[n:]
+		fd := md.Fields().ByNumber(num)
+		if fd == nil {
+			continue
+		}
+
+		path := prefix + string(fd.Name())
+		switch {
+		case fd.IsMap():
+			sanitizeEntry(v, fd, path, paths)
+			continue
+		case fd.IsList():
+			path = fmt.Sprintf("%v[%v]", path, counts[num])
+			counts[num]++
+		}
+		switch {
+		case fd.Message() != nil:
+			sanitize(v, fd.Message(), path+".", paths)
+		case fd.Kind() == protoreflect.StringKind && !utf8.Valid(v):
+			*paths = append(*paths, path)
+			replaceInvalid(v)
+		}
+	}
+}
+
+// sanitizeEntry sanitizes b, the encoding of an entry of the map field fd,
+// naming the value of the entry by its key like check does.
+func sanitizeEntry(b []byte, fd protoreflect.FieldDescriptor, path string, paths *[]string) {
+	var entryPaths []string
+	sanitize(b, fd.Message(), "", &entryPaths)
+	if len(entryPaths) == 0 {
+		return
+	}
+	entry := dynamicpb.NewMessage(fd.Message())
+	if err := proto.Unmarshal(b, entry); err != nil {
+		return
+	}
+	key := entry.Get(fd.MapKey()).MapKey().String()
+	for _, p := range entryPaths {
+		if p == "key" {
+			*paths = append(*paths, path)
+			continue
+		}
+		*paths = append(*paths, fmt.Sprintf("%v[%v]%v", path, key, strings.TrimPrefix(p, "value")))
+	}
+}
+
+func replaceInvalid(b []byte) {
+	for i := 0; i < len(b); {
+		r, n := utf8.DecodeRune(b[i:])
+		if r == utf8.RuneError && n == 1 {
+			b[i] = '?'
+		}
+		i += n
+	}
+}
+
+// invalidUTF8 removes the fields Codec added to m and returns the paths they
+// hold.
+func invalidUTF8(m protoreflect.Message) []string {
+	var paths []string
+	unknown := m.GetUnknown()
+	kept := unknown[:0:0]
+	for b := unknown; len(b) > 0; {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return paths
+		}
+		l := protowire.ConsumeFieldValue(num, typ, b[n:])
+		if l < 0 {
+			return paths
+		}
+		if num == invalidUTF8Field && typ == protowire.BytesType {
+			v, _ := protowire.ConsumeString(b[n:])
+			paths = append(paths, v)
+		} else {
+			kept = append(kept, b[:n+l]...)
+		}
+		b = b[n+l:]
+	}
+	if paths != nil {
+		m.SetUnknown(kept)
+	}
+	return paths
+}
@@ -0,0 +1,166 @@
+// Package validate checks incoming requests against the rules declared on
+// their fields with the (validate.rules) option from validatepb:
+//
+//	string first_name = 1 [(validate.rules) = {required: true, max_len: 100}];
+//
+// Invalid requests are rejected with codes.InvalidArgument and a
+// google.rpc.BadRequest detail listing every violated field. String fields
+// must always be valid UTF-8, which is only reported as a violation by
+// servers decoding requests with Codec: others fail such requests with
+// codes.Internal while decoding them.
+package validate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go-grpc/validate/validatepb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Message checks m and returns an InvalidArgument status error describing
+// every violation, or nil if m is valid.
+func Message(m proto.Message) error {
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, path := range invalidUTF8(m.ProtoReflect()) {
+		violate(&violations, path, "must be valid UTF-8")
+	}
+	check(m.ProtoReflect(), "", &violations)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	descs := make([]string, len(violations))
+	for i, v := range violations {
+		descs[i] = v.GetField() + " " + v.GetDescription()
+	}
+	st := status.New(codes.InvalidArgument, "Invalid request: "+strings.Join(descs, "; "))
+	if d, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = d
+	}
+	return st.Err()
+}
+
+// UnaryServerInterceptor validates the request of unary RPCs.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if m, ok := req.(proto.Message); ok {
+			if err := Message(m); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor validates every message received on streaming
+// RPCs. An invalid message fails the RecvMsg call that read it.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ss})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if pm, ok := m.(proto.Message); ok {
+		return Message(pm)
+	}
+	return nil
+}
+
+// check appends the violations found in m to violations. Field paths are
+// prefixed with prefix.
+func check(m protoreflect.Message, prefix string, violations *[]*errdetails.BadRequest_FieldViolation) {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		path := prefix + string(fd.Name())
+		rules, _ := proto.GetExtension(fd.Options(), validatepb.E_Rules).(*validatepb.FieldRules)
+
+		switch {
+		case fd.IsMap():
+			mp := m.Get(fd).Map()
+			if rules.GetRequired() && mp.Len() == 0 {
+				violate(violations, path, "is required")
+			}
+			// Keys are sorted so that violations are listed in a stable
+			// order.
+			var keys []protoreflect.MapKey
+			mp.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys = append(keys, k)
+				return true
+			})
+			sort.Slice(keys, func(a, b int) bool { return keys[a].String() < keys[b].String() })
+			for _, k := range keys {
+				checkElement(mp.Get(k), fd.MapValue(), fmt.Sprintf("%v[%v]", path, k.String()), rules, violations)
+			}
+		case fd.IsList():
+			l := m.Get(fd).List()
+			if rules.GetRequired() && l.Len() == 0 {
+				violate(violations, path, "is required")
+			}
+			for j := 0; j < l.Len(); j++ {
+				checkElement(l.Get(j), fd, fmt.Sprintf("%v[%v]", path, j), rules, violations)
+			}
+		case fd.Message() != nil:
+			if !m.Has(fd) {
+				if rules.GetRequired() {
+					violate(violations, path, "is required")
+				}
+				continue
+			}
+			check(m.Get(fd).Message(), path+".", violations)
+		case fd.Kind() == protoreflect.StringKind:
+			checkString(m.Get(fd).String(), path, rules, violations)
+		}
+	}
+}
+
+// checkElement checks v, an element of a list or a value of a map described
+// by fd, at path. Rules other than required apply to every element.
+func checkElement(v protoreflect.Value, fd protoreflect.FieldDescriptor, path string, rules *validatepb.FieldRules, violations *[]*errdetails.BadRequest_FieldViolation) {
+	switch {
+	case fd.Message() != nil:
+		check(v.Message(), path+".", violations)
+	case fd.Kind() == protoreflect.StringKind:
+		checkString(v.String(), path, &validatepb.FieldRules{
+			MaxLen:         rules.GetMaxLen(),
+			NoControlChars: rules.GetNoControlChars(),
+		}, violations)
+	}
+}
+
+func checkString(s, path string, rules *validatepb.FieldRules, violations *[]*errdetails.BadRequest_FieldViolation) {
+	if rules.GetRequired() && s == "" {
+		violate(violations, path, "must not be empty")
+	}
+	if max := rules.GetMaxLen(); max > 0 && utf8.RuneCountInString(s) > int(max) {
+		violate(violations, path, fmt.Sprintf("must be at most %v characters long", max))
+	}
+	if rules.GetNoControlChars() && strings.IndexFunc(s, unicode.IsControl) >= 0 {
+		violate(violations, path, "must not contain control characters")
+	}
+}
+
+func violate(violations *[]*errdetails.BadRequest_FieldViolation, field, desc string) {
+	*violations = append(*violations, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: desc,
+	})
+}
@@ -0,0 +1,158 @@
+package validate_test
+
+import (
+	"testing"
+
+	"go-grpc/validate"
+	"go-grpc/validate/validatepb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// labelsMessage describes a message with a required map field, as declared
+// by
+//
+//	map<string, string> labels = 1 [(validate.rules) = {required: true, max_len: 5}];
+//
+// No generated message has one, so it is built at run time.
+func labelsMessage(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+	opts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(opts, validatepb.E_Rules, &validatepb.FieldRules{Required: true, MaxLen: 5})
+	field := func(name string, number int32) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			JsonName: proto.String(name),
+		}
+	}
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("validate_test/labels.proto"),
+		Package:    proto.String("validate_test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"validate/validatepb/validate.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Labels"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("labels"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+				TypeName: proto.String(".validate_test.Labels.LabelsEntry"),
+				JsonName: proto.String("labels"),
+				Options:  opts,
+			}},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name:    proto.String("LabelsEntry"),
+				Field:   []*descriptorpb.FieldDescriptorProto{field("key", 1), field("value", 2)},
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+		}},
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile() error = %v", err)
+	}
+	return fd.Messages().Get(0)
+}
+
+func TestMap(t *testing.T) {
+	md := labelsMessage(t)
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   []string
+	}{
+		{"empty", nil, []string{"labels is required"}},
+		{"valid", map[string]string{"a": "one", "b": "two"}, nil},
+		{"long values", map[string]string{"b": "second", "a": "first!", "c": "ok"}, []string{
+			"labels[a] must be at most 5 characters long",
+			"labels[b] must be at most 5 characters long",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := dynamicpb.NewMessage(md)
+			fd := md.Fields().ByName("labels")
+			labels := m.Mutable(fd).Map()
+			for k, v := range tt.labels {
+				labels.Set(protoreflect.ValueOfString(k).MapKey(), protoreflect.ValueOfString(v))
+			}
+
+			err := validate.Message(m)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Message() error = %v, want nil", err)
+				}
+				return
+			}
+			st := status.Convert(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("Message() error = %v, want code %v", err, codes.InvalidArgument)
+			}
+			var got []string
+			for _, d := range st.Details() {
+				if br, ok := d.(*errdetails.BadRequest); ok {
+					for _, v := range br.GetFieldViolations() {
+						got = append(got, v.GetField()+" "+v.GetDescription())
+					}
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("violations = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("violations = %q, want %q", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestCodecInvalidUTF8(t *testing.T) {
+	md := labelsMessage(t)
+	var entry []byte
+	entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+	entry = protowire.AppendString(entry, "a")
+	entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+	entry = protowire.AppendString(entry, "\xff")
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendBytes(b, entry)
+
+	m := dynamicpb.NewMessage(md)
+	if err := proto.Unmarshal(b, m); err == nil {
+		t.Fatal("proto.Unmarshal() accepted invalid UTF-8, want an error")
+	}
+	m = dynamicpb.NewMessage(md)
+	if err := (validate.Codec{}).Unmarshal(b, m); err != nil {
+		t.Fatalf("Codec.Unmarshal() error = %v", err)
+	}
+	st := status.Convert(validate.Message(m))
+	var got []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				got = append(got, v.GetField()+" "+v.GetDescription())
+			}
+		}
+	}
+	if want := "labels[a] must be valid UTF-8"; len(got) != 1 || got[0] != want {
+		t.Errorf("violations = %q, want [%q]", got, want)
+	}
+	if len(m.GetUnknown()) != 0 {
+		t.Errorf("unknown fields = %x after Message, want none", m.GetUnknown())
+	}
+}